@@ -44,6 +44,38 @@ var file_annotations_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "bytes,72295729,opt,name=documentation",
 		Filename:      "annotations.proto",
 	},
+	{
+		ExtendedType:  (*descriptor.FieldOptions)(nil),
+		ExtensionType: (*Field)(nil),
+		Field:         72295730,
+		Name:          "custom.field",
+		Tag:           "bytes,72295730,opt,name=field",
+		Filename:      "annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptor.FileOptions)(nil),
+		ExtensionType: (*CORS)(nil),
+		Field:         72295731,
+		Name:          "custom.file_cors",
+		Tag:           "bytes,72295731,opt,name=file_cors",
+		Filename:      "annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptor.FileOptions)(nil),
+		ExtensionType: (*JSONOptions)(nil),
+		Field:         72295733,
+		Name:          "custom.file_json",
+		Tag:           "bytes,72295733,opt,name=file_json",
+		Filename:      "annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptor.ServiceOptions)(nil),
+		ExtensionType: (*CORS)(nil),
+		Field:         72295732,
+		Name:          "custom.service_cors",
+		Tag:           "bytes,72295732,opt,name=service_cors",
+		Filename:      "annotations.proto",
+	},
 }
 
 // Extension fields to descriptor.MethodOptions.
@@ -54,6 +86,33 @@ var (
 	E_Documentation = &file_annotations_proto_extTypes[0]
 )
 
+// Extension fields to descriptor.FieldOptions.
+var (
+	// optional custom.Field field = 72295730;
+	E_Field = &file_annotations_proto_extTypes[1]
+)
+
+// Extension fields to descriptor.FileOptions.
+var (
+	// CORS policy of the services of the file.
+	//
+	// optional custom.CORS file_cors = 72295731;
+	E_FileCors = &file_annotations_proto_extTypes[2]
+	// JSON marshaling of the services of the file, overriding the plugin
+	// parameters. Rpcs can override it further through their documentation.
+	//
+	// optional custom.JSONOptions file_json = 72295733;
+	E_FileJson = &file_annotations_proto_extTypes[3]
+)
+
+// Extension fields to descriptor.ServiceOptions.
+var (
+	// CORS policy of the service, replacing the one of the file.
+	//
+	// optional custom.CORS service_cors = 72295732;
+	E_ServiceCors = &file_annotations_proto_extTypes[4]
+)
+
 var File_annotations_proto protoreflect.FileDescriptor
 
 var file_annotations_proto_rawDesc = []byte{
@@ -68,24 +127,58 @@ var file_annotations_proto_rawDesc = []byte{
 	0x6f, 0x6e, 0x73, 0x18, 0xb1, 0xca, 0xbc, 0x22, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63,
 	0x75, 0x73, 0x74, 0x6f, 0x6d, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74,
 	0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x42, 0x20, 0x5a, 0x1e, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x2f, 0x61, 0x6e, 0x6e,
-	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x3b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x6e, 0x3a, 0x45, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb2, 0xca, 0xbc, 0x22, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x2e, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x3a, 0x4a, 0x0a, 0x09, 0x66, 0x69, 0x6c,
+	0x65, 0x5f, 0x63, 0x6f, 0x72, 0x73, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb3, 0xca, 0xbc, 0x22, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x2e, 0x43, 0x4f, 0x52, 0x53, 0x52, 0x08, 0x66, 0x69, 0x6c,
+	0x65, 0x43, 0x6f, 0x72, 0x73, 0x3a, 0x51, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6a, 0x73,
+	0x6f, 0x6e, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0xb5, 0xca, 0xbc, 0x22, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x75, 0x73, 0x74,
+	0x6f, 0x6d, 0x2e, 0x4a, 0x53, 0x4f, 0x4e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x4a, 0x73, 0x6f, 0x6e, 0x3a, 0x53, 0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x5f, 0x63, 0x6f, 0x72, 0x73, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb4, 0xca, 0xbc, 0x22, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x2e, 0x43, 0x4f, 0x52, 0x53,
+	0x52, 0x0b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x72, 0x73, 0x42, 0x20, 0x5a,
+	0x1e, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x3b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_annotations_proto_goTypes = []interface{}{
-	(*descriptor.MethodOptions)(nil), // 0: google.protobuf.MethodOptions
-	(*Documentation)(nil),            // 1: custom.Documentation
+	(*descriptor.MethodOptions)(nil),  // 0: google.protobuf.MethodOptions
+	(*descriptor.FieldOptions)(nil),   // 1: google.protobuf.FieldOptions
+	(*descriptor.FileOptions)(nil),    // 2: google.protobuf.FileOptions
+	(*descriptor.ServiceOptions)(nil), // 3: google.protobuf.ServiceOptions
+	(*Documentation)(nil),             // 4: custom.Documentation
+	(*Field)(nil),                     // 5: custom.Field
+	(*CORS)(nil),                      // 6: custom.CORS
+	(*JSONOptions)(nil),               // 7: custom.JSONOptions
 }
 
 var file_annotations_proto_depIdxs = []int32{
-	0, // 0: custom.documentation:extendee -> google.protobuf.MethodOptions
-	1, // 1: custom.documentation:type_name -> custom.Documentation
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	1, // [1:2] is the sub-list for extension type_name
-	0, // [0:1] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: custom.documentation:extendee -> google.protobuf.MethodOptions
+	1,  // 1: custom.field:extendee -> google.protobuf.FieldOptions
+	2,  // 2: custom.file_cors:extendee -> google.protobuf.FileOptions
+	2,  // 3: custom.file_json:extendee -> google.protobuf.FileOptions
+	3,  // 4: custom.service_cors:extendee -> google.protobuf.ServiceOptions
+	4,  // 5: custom.documentation:type_name -> custom.Documentation
+	5,  // 6: custom.field:type_name -> custom.Field
+	6,  // 7: custom.file_cors:type_name -> custom.CORS
+	7,  // 8: custom.file_json:type_name -> custom.JSONOptions
+	6,  // 9: custom.service_cors:type_name -> custom.CORS
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	5,  // [5:10] is the sub-list for extension type_name
+	0,  // [0:5] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_annotations_proto_init() }
@@ -93,6 +186,7 @@ func file_annotations_proto_init() {
 	if File_annotations_proto != nil {
 		return
 	}
+	file_documentation_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -100,7 +194,7 @@ func file_annotations_proto_init() {
 			RawDescriptor: file_annotations_proto_rawDesc,
 			NumEnums:      0,
 			NumMessages:   0,
-			NumExtensions: 1,
+			NumExtensions: 5,
 			NumServices:   0,
 		},
 		GoTypes:           file_annotations_proto_goTypes,
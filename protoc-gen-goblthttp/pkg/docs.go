@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	_ "embed"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+//go:embed docs.html
+var docsPage string
+
+// GenerateHTTPDocs generates the docs page along with the functions serving
+// it and the embedded OpenAPI spec
+func GenerateHTTPDocs(
+	srvs []Server,
+	g *protogen.GeneratedFile,
+	ghtml *protogen.GeneratedFile,
+	file *protogen.File,
+) error {
+	ghtml.P(strings.TrimSuffix(docsPage, "\n"))
+
+	base := filepath.Base(file.GeneratedFilenamePrefix)
+	varPrefix := "file_" + strings.TrimPrefix(file.GoDescriptorIdent.GoName, "File_")
+	specName := varPrefix + "_openAPISpec"
+	pageName := varPrefix + "_docsPage"
+
+	g.Import(embedPackage)
+	g.P("//go:embed ", base, ".http.json")
+	g.P("var ", specName, " []byte")
+	g.P("")
+	g.P("//go:embed ", base, ".http.html")
+	g.P("var ", pageName, " string")
+
+	if len(srvs) == 0 {
+		return nil
+	}
+
+	// the spec covers every service of the file, so it is served once per
+	// file rather than once per service
+	name := permissionIdent(
+		"Register",
+		strings.TrimSuffix(filepath.Base(file.Desc.Path()), ".proto"),
+	) + "HTTPDocs"
+	g.P("")
+	g.P("// ", name, " registers a docs page of the services in ", file.Desc.Path())
+	g.P("// under the route along with the OpenAPI spec under route/openapi.json,")
+	g.P("// neither of which require network access outside of the service")
+	g.P("func ", name, "(")
+	g.P("grp *", ginPackage.Ident("RouterGroup"), ",")
+	g.P("route string,")
+	g.P(") {")
+	g.P("specRoute := ", pathPackage.Ident("Join"), "(route, \"openapi.json\")")
+	g.P("page := []byte(", stringsPackage.Ident("Replace"), "(")
+	g.P(pageName, ",")
+	g.P("\"{{SPEC_URL}}\",")
+	g.P(pathPackage.Ident("Join"), "(grp.BasePath(), specRoute),")
+	g.P("1,")
+	g.P("))")
+	g.P("grp.GET(specRoute, func(ctx *", ginPackage.Ident("Context"), ") {")
+	g.P("	ctx.Data(200, \"application/json\", ", specName, ")")
+	g.P("})")
+	g.P("grp.GET(route, func(ctx *", ginPackage.Ident("Context"), ") {")
+	g.P("	ctx.Data(200, \"text/html; charset=utf-8\", page)")
+	g.P("})")
+	g.P("}")
+	return nil
+}
@@ -4,11 +4,14 @@ package pkg
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,9 +25,41 @@ const (
 	gorrPackage      = protogen.GoImportPath("github.com/betalixt/gorr")
 	strconvPackage   = protogen.GoImportPath("strconv")
 	timePackage      = protogen.GoImportPath("time")
-	timepbPackage    = protogen.GoImportPath("google.golang.org/protobuf/types/known/timestamppb")
-	stringsPackage   = protogen.GoImportPath("strings")
-	grpcPackage      = protogen.GoImportPath("google.golang.org/grpc")
+	timepbPackage    = protogen.GoImportPath(
+		"google.golang.org/protobuf/types/known/timestamppb",
+	)
+	stringsPackage      = protogen.GoImportPath("strings")
+	grpcPackage         = protogen.GoImportPath("google.golang.org/grpc")
+	base64Package       = protogen.GoImportPath("encoding/base64")
+	pathPackage         = protogen.GoImportPath("path")
+	protoPackage        = protogen.GoImportPath("google.golang.org/protobuf/proto")
+	syncPackage         = protogen.GoImportPath("sync")
+	mathPackage         = protogen.GoImportPath("math")
+	sha256Package       = protogen.GoImportPath("crypto/sha256")
+	errorsPackage       = protogen.GoImportPath("errors")
+	jsonPackage         = protogen.GoImportPath("encoding/json")
+	bytesPackage        = protogen.GoImportPath("bytes")
+	protoreflectPackage = protogen.GoImportPath(
+		"google.golang.org/protobuf/reflect/protoreflect",
+	)
+	hexPackage       = protogen.GoImportPath("encoding/hex")
+	sortPackage      = protogen.GoImportPath("sort")
+	fieldmaskPackage = protogen.GoImportPath("google.golang.org/protobuf/types/known/fieldmaskpb")
+	protoregPackage  = protogen.GoImportPath("google.golang.org/protobuf/reflect/protoregistry")
+	otelPackage      = protogen.GoImportPath("go.opentelemetry.io/otel")
+	oteltracePackage = protogen.GoImportPath("go.opentelemetry.io/otel/trace")
+	otelmetPackage   = protogen.GoImportPath("go.opentelemetry.io/otel/metric")
+	otelnoopPackage  = protogen.GoImportPath("go.opentelemetry.io/otel/metric/noop")
+	otelattrPackage  = protogen.GoImportPath("go.opentelemetry.io/otel/attribute")
+	otelcodesPackage = protogen.GoImportPath("go.opentelemetry.io/otel/codes")
+	otelpropPackage  = protogen.GoImportPath("go.opentelemetry.io/otel/propagation")
+	ioPackage        = protogen.GoImportPath("io")
+	mimePackage      = protogen.GoImportPath("mime")
+	nethttpPackage   = protogen.GoImportPath("net/http")
+	metadataPackage  = protogen.GoImportPath("google.golang.org/grpc/metadata")
+	statusPackage    = protogen.GoImportPath("google.golang.org/grpc/status")
+	codesPackage     = protogen.GoImportPath("google.golang.org/grpc/codes")
+	multipartPackage = protogen.GoImportPath("mime/multipart")
 )
 
 // GenerateHTTPServers generates http servers
@@ -32,6 +67,7 @@ func GenerateHTTPServers(
 	srvs []Server,
 	g *protogen.GeneratedFile,
 	_ *protogen.File,
+	opts Options,
 ) error {
 	g.P(
 		"func newMissingRequiredParametersError(parameter string) *",
@@ -48,6 +84,38 @@ func GenerateHTTPServers(
 	g.P(")")
 	g.P("}")
 	g.P("")
+	g.P("// newMissingFormValuesError values of a streamed multipart body are only")
+	g.P("// read up to its file part, so values sent after it are missing as well")
+	g.P(
+		"func newMissingFormValuesError(parameter string) *",
+		gorrPackage.Ident("Error"),
+		"{",
+	)
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    400,")
+	g.P("		Message: \"MissingRequiredParametersError\",")
+	g.P("	},")
+	g.P("	400,")
+	g.P("	\"missing field(s), or sent after the file part: \"+parameter,")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P(
+		"func newFormValueAfterFileError(parameter string) *",
+		gorrPackage.Ident("Error"),
+		"{",
+	)
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    400,")
+	g.P("		Message: \"FormValueAfterFileError\",")
+	g.P("	},")
+	g.P("	400,")
+	g.P("	\"field(s) have to be sent before the file part: \"+parameter,")
+	g.P(")")
+	g.P("}")
+	g.P("")
 	g.P("func newUnparsableParameterError(parameter string) *", gorrPackage.Ident("Error"), "{")
 	g.P("return ", gorrPackage.Ident("NewError"), "(")
 	g.P(gorrPackage.Ident("ErrorCode"), "{")
@@ -58,97 +126,1211 @@ func GenerateHTTPServers(
 	g.P("	\"failed to parsed or missing field(s): \"+parameter,")
 	g.P(")")
 	g.P("}")
-
+	g.P("")
+	g.P("func newForbiddenError() *", gorrPackage.Ident("Error"), "{")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    403,")
+	g.P("		Message: \"ForbiddenError\",")
+	g.P("	},")
+	g.P("	403,")
+	g.P("	\"insufficient permissions to access resource\",")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("func newFeatureDisabledError(status int) *", gorrPackage.Ident("Error"), "{")
+	g.P("if status == 403 {")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    403,")
+	g.P("		Message: \"FeatureDisabledError\",")
+	g.P("	},")
+	g.P("	403,")
+	g.P("	\"feature is not enabled\",")
+	g.P(")")
+	g.P("}")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    404,")
+	g.P("		Message: \"NotFoundError\",")
+	g.P("	},")
+	g.P("	404,")
+	g.P("	\"resource not found\",")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("func newTooManyRequestsError() *", gorrPackage.Ident("Error"), "{")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    429,")
+	g.P("		Message: \"TooManyRequestsError\",")
+	g.P("	},")
+	g.P("	429,")
+	g.P("	\"rate limit exceeded\",")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("func newRequestTooLargeError() *", gorrPackage.Ident("Error"), "{")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    413,")
+	g.P("		Message: \"RequestTooLargeError\",")
+	g.P("	},")
+	g.P("	413,")
+	g.P("	\"request body is too large\",")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("func newUnsupportedMediaTypeError() *", gorrPackage.Ident("Error"), "{")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    415,")
+	g.P("		Message: \"UnsupportedMediaTypeError\",")
+	g.P("	},")
+	g.P("	415,")
+	g.P("	\"content type of the request body is not supported\",")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("func newGatewayTimeoutError() *", gorrPackage.Ident("Error"), "{")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    504,")
+	g.P("		Message: \"GatewayTimeoutError\",")
+	g.P("	},")
+	g.P("	504,")
+	g.P("	\"deadline exceeded\",")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("func newPreconditionRequiredError() *", gorrPackage.Ident("Error"), "{")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    428,")
+	g.P("		Message: \"PreconditionRequiredError\",")
+	g.P("	},")
+	g.P("	428,")
+	g.P("	\"missing If-Match header\",")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("func newPreconditionFailedError() *", gorrPackage.Ident("Error"), "{")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    412,")
+	g.P("		Message: \"PreconditionFailedError\",")
+	g.P("	},")
+	g.P("	412,")
+	g.P("	\"resource has been modified\",")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("func newIdempotencyConflictError(message string) *", gorrPackage.Ident("Error"), "{")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    409,")
+	g.P("		Message: \"IdempotencyKeyConflictError\",")
+	g.P("	},")
+	g.P("	409,")
+	g.P("	message,")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("// decodeBytesParameter decodes a bytes parameter the same way protojson")
+	g.P("// does, accepting standard or url safe base64 with or without padding")
+	g.P("func decodeBytesParameter(val string) ([]byte, error) {")
+	g.P("enc := ", base64Package.Ident("StdEncoding"))
+	g.P("if ", stringsPackage.Ident("ContainsAny"), "(val, \"-_\") {")
+	g.P("	enc = ", base64Package.Ident("URLEncoding"))
+	g.P("}")
+	g.P("if len(val)%4 != 0 {")
+	g.P("	enc = enc.WithPadding(", base64Package.Ident("NoPadding"), ")")
+	g.P("}")
+	g.P("return enc.DecodeString(val)")
+	g.P("}")
+	g.P("")
+	g.P("// responseETag strong etag of a marshaled response")
+	g.P("func responseETag(raw []byte) string {")
+	g.P("sum := ", sha256Package.Ident("Sum256"), "(raw)")
+	g.P("return \"\\\"\" + ", hexPackage.Ident("EncodeToString"), "(sum[:16]) + \"\\\"\"")
+	g.P("}")
+	g.P("")
+	g.P("// versionETag strong etag of a response from its version field")
+	g.P("func versionETag(version string) string {")
+	g.P(
+		"return \"\\\"\" + ",
+		stringsPackage.Ident("ReplaceAll"),
+		"(version, \"\\\"\", \"\") + \"\\\"\"",
+	)
+	g.P("}")
+	g.P("")
+	g.P("// parseIfMatch strong entity-tags listed in an If-Match header, weak tags are")
+	g.P("// left out as they never match the strong comparison If-Match uses")
+	g.P("func parseIfMatch(header string) ([]string, bool) {")
+	g.P("tags := []string{}")
+	g.P("for _, tag := range ", stringsPackage.Ident("Split"), "(header, \",\") {")
+	g.P("	tag = ", stringsPackage.Ident("TrimSpace"), "(tag)")
+	g.P("	if tag == \"\" {")
+	g.P("		continue")
+	g.P("	}")
+	g.P("	weak := ", stringsPackage.Ident("HasPrefix"), "(tag, \"W/\")")
+	g.P("	tag = ", stringsPackage.Ident("TrimPrefix"), "(tag, \"W/\")")
+	g.P("	if len(tag) < 2 || tag[0] != '\"' || tag[len(tag)-1] != '\"' {")
+	g.P("		return nil, false")
+	g.P("	}")
+	g.P("	if !weak {")
+	g.P("		tags = append(tags, tag[1:len(tag)-1])")
+	g.P("	}")
+	g.P("}")
+	g.P("return tags, true")
+	g.P("}")
+	g.P("")
+	g.P("// etagMatches checks an If-None-Match or If-Match header against an etag")
+	g.P("func etagMatches(header string, etag string) bool {")
+	g.P("if header == \"\" {")
+	g.P("	return false")
+	g.P("}")
+	g.P("for _, tag := range ", stringsPackage.Ident("Split"), "(header, \",\") {")
+	g.P("	tag = ", stringsPackage.Ident("TrimSpace"), "(tag)")
+	g.P("	if tag == \"*\" || ", stringsPackage.Ident("TrimPrefix"), "(tag, \"W/\") == etag {")
+	g.P("		return true")
+	g.P("	}")
+	g.P("}")
+	g.P("return false")
+	g.P("}")
+	g.P("")
+	g.P("// nextPageLink RFC 8288 link to the next page of a paginated query")
+	g.P(
+		"func nextPageLink(ctx *",
+		ginPackage.Ident("Context"),
+		", tokenKey string, token string) string {",
+	)
+	g.P("query := ctx.Request.URL.Query()")
+	g.P("query.Set(tokenKey, token)")
+	g.P("return \"<\" + ctx.Request.URL.Path + \"?\" + query.Encode() + \">; rel=\\\"next\\\"\"")
+	g.P("}")
+	g.P("")
+	g.P("type streamedFieldKey struct{ field string }")
+	g.P("")
+	g.P("// formValues form values of a streamed multipart body, read before its")
+	g.P("// file part")
+	g.P("type formValues map[string][]string")
+	g.P("")
+	g.P("func (f formValues) FormArray(key string) []string {")
+	g.P("	return f[key]")
+	g.P("}")
+	g.P("")
+	g.P("func (f formValues) GetForm(key string) (string, bool) {")
+	g.P("if vals := f[key]; len(vals) != 0 {")
+	g.P("	return vals[0], true")
+	g.P("}")
+	g.P("return \"\", false")
+	g.P("}")
+	g.P("")
+	g.P("// formFileReader reads the file part of a streamed multipart body, once it")
+	g.P("// is drained the parts after it are rejected since their values were bound")
+	g.P("// before the file was read")
+	g.P("type formFileReader struct {")
+	g.P("part   *", multipartPackage.Ident("Part"))
+	g.P("reader *", multipartPackage.Ident("Reader"))
+	g.P("err    error")
+	g.P("}")
+	g.P("")
+	g.P("func (r *formFileReader) Read(p []byte) (int, error) {")
+	g.P("if r.err != nil {")
+	g.P("	return 0, r.err")
+	g.P("}")
+	g.P("n, err := r.part.Read(p)")
+	g.P("if !", errorsPackage.Ident("Is"), "(err, ", ioPackage.Ident("EOF"), ") {")
+	g.P("	return n, err")
+	g.P("}")
+	g.P("next, err := r.reader.NextPart()")
+	g.P("switch {")
+	g.P("case ", errorsPackage.Ident("Is"), "(err, ", ioPackage.Ident("EOF"), "):")
+	g.P("	r.err = ", ioPackage.Ident("EOF"))
+	g.P("case err != nil:")
+	g.P("	r.err = bodyReadError(err)")
+	g.P("default:")
+	g.P("	r.err = newFormValueAfterFileError(next.FormName())")
+	g.P("}")
+	g.P("return n, r.err")
+	g.P("}")
+	g.P("")
+	g.P("// readFormValues reads the parts of a multipart body up to the file part of")
+	g.P("// the name, which is returned unread, the values of the parts before it are")
+	g.P("// collected as the form")
+	g.P(
+		"func readFormValues(ctx *",
+		ginPackage.Ident("Context"),
+		", name string) (formValues, *formFileReader, error) {",
+	)
+	g.P("reader, err := ctx.Request.MultipartReader()")
+	g.P("if err != nil {")
+	g.P(
+		"if ",
+		errorsPackage.Ident("Is"),
+		"(err, ",
+		nethttpPackage.Ident("ErrNotMultipart"),
+		") {",
+	)
+	g.P("	return nil, nil, newUnsupportedMediaTypeError()")
+	g.P("}")
+	g.P("return nil, nil, bodyReadError(err)")
+	g.P("}")
+	g.P("form := formValues{}")
+	g.P("for {")
+	g.P("part, err := reader.NextPart()")
+	g.P("if ", errorsPackage.Ident("Is"), "(err, ", ioPackage.Ident("EOF"), ") {")
+	g.P("	return form, nil, nil")
+	g.P("}")
+	g.P("if err != nil {")
+	g.P("	return nil, nil, bodyReadError(err)")
+	g.P("}")
+	g.P("if part.FormName() == name {")
+	g.P("	return form, &formFileReader{part: part, reader: reader}, nil")
+	g.P("}")
+	g.P("val, err := ", ioutilPackage.Ident("ReadAll"), "(part)")
+	g.P("if err != nil {")
+	g.P("	return nil, nil, bodyReadError(err)")
+	g.P("}")
+	g.P("form[part.FormName()] = append(form[part.FormName()], string(val))")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// contentTypeAllowed checks the media type of the request body against the")
+	g.P("// ones accepted")
+	g.P("func contentTypeAllowed(ctx *", ginPackage.Ident("Context"), ", accepted []string) bool {")
+	g.P(
+		"mediaType, _, err := ",
+		mimePackage.Ident("ParseMediaType"),
+		"(ctx.GetHeader(\"Content-Type\"))",
+	)
+	g.P("if err != nil {")
+	g.P("	return false")
+	g.P("}")
+	g.P("for _, val := range accepted {")
+	g.P("	if ", stringsPackage.Ident("EqualFold"), "(val, mediaType) {")
+	g.P("		return true")
+	g.P("	}")
+	g.P("}")
+	g.P("return false")
+	g.P("}")
+	g.P("")
+	g.P("// bodyReadError maps errors reading a limited request body to 413")
+	g.P("func bodyReadError(err error) error {")
+	g.P("var maxErr *", nethttpPackage.Ident("MaxBytesError"))
+	g.P("if ", errorsPackage.Ident("As"), "(err, &maxErr) {")
+	g.P("	return newRequestTooLargeError()")
+	g.P("}")
+	g.P("return err")
+	g.P("}")
+	g.P("")
+	g.P("// readFormFile reads the multipart file part of the name, reporting whether")
+	g.P("// it was sent")
+	g.P(
+		"func readFormFile(ctx *",
+		ginPackage.Ident("Context"),
+		", name string) ([]byte, bool, error) {",
+	)
+	g.P("header, err := ctx.FormFile(name)")
+	g.P("if err != nil {")
+	g.P("	return nil, false, nil")
+	g.P("}")
+	g.P("f, err := header.Open()")
+	g.P("if err != nil {")
+	g.P("	return nil, true, err")
+	g.P("}")
+	g.P("defer f.Close()")
+	g.P("data, err := ", ioutilPackage.Ident("ReadAll"), "(f)")
+	g.P("return data, true, bodyReadError(err)")
+	g.P("}")
+	g.P("")
+	g.P("// requestTimeout deadline asked for by the client through the grpc-timeout")
+	g.P("// or Request-Timeout (in seconds) headers, malformed values are ignored")
+	g.P(
+		"func requestTimeout(ctx *",
+		ginPackage.Ident("Context"),
+		") (",
+		timePackage.Ident("Duration"),
+		", bool) {",
+	)
+	g.P("if val := ctx.GetHeader(\"grpc-timeout\"); len(val) >= 2 && len(val) <= 9 {")
+	g.P("units := map[byte]", timePackage.Ident("Duration"), "{")
+	g.P("	'H': ", timePackage.Ident("Hour"), ",")
+	g.P("	'M': ", timePackage.Ident("Minute"), ",")
+	g.P("	'S': ", timePackage.Ident("Second"), ",")
+	g.P("	'm': ", timePackage.Ident("Millisecond"), ",")
+	g.P("	'u': ", timePackage.Ident("Microsecond"), ",")
+	g.P("	'n': ", timePackage.Ident("Nanosecond"), ",")
+	g.P("}")
+	g.P("n, err := ", strconvPackage.Ident("ParseUint"), "(val[:len(val)-1], 10, 32)")
+	g.P("unit, ok := units[val[len(val)-1]]")
+	g.P(
+		"if ok && err == nil && n > 0 && n <= uint64(",
+		mathPackage.Ident("MaxInt64"),
+		"/int64(unit)) {",
+	)
+	g.P("	return ", timePackage.Ident("Duration"), "(n) * unit, true")
+	g.P("}")
+	g.P("}")
+	g.P("if val := ctx.GetHeader(\"Request-Timeout\"); val != \"\" {")
+	g.P("secs, err := ", strconvPackage.Ident("ParseFloat"), "(val, 64)")
+	g.P(
+		"if err == nil && secs > 0 && secs < ",
+		mathPackage.Ident("MaxInt64"),
+		"/float64(",
+		timePackage.Ident("Second"),
+		") {",
+	)
+	g.P(
+		"	return ",
+		timePackage.Ident("Duration"),
+		"(secs * float64(",
+		timePackage.Ident("Second"),
+		")), true",
+	)
+	g.P("}")
+	g.P("}")
+	g.P("return 0, false")
+	g.P("}")
+	g.P("")
+	g.P("// fieldsMask json names of the fields selected by a fields parameter, nil")
+	g.P("// masks select the whole field")
+	g.P("type fieldsMask map[string]fieldsMask")
+	g.P("")
+	g.P("// parseFieldsParameter parses a comma separated list of field paths against")
+	g.P("// the descriptor of a response, accepting json or proto field names")
+	g.P("func parseFieldsParameter(")
+	g.P("val string,")
+	g.P("desc ", protoreflectPackage.Ident("MessageDescriptor"), ",")
+	g.P(") (fieldsMask, bool) {")
+	g.P("if val == \"\" {")
+	g.P("	return nil, true")
+	g.P("}")
+	g.P("mask := fieldsMask{}")
+	g.P("for _, path := range ", stringsPackage.Ident("Split"), "(val, \",\") {")
+	g.P(
+		"segs := ",
+		stringsPackage.Ident("Split"),
+		"(",
+		stringsPackage.Ident("TrimSpace"),
+		"(path), \".\")",
+	)
+	g.P("node, md := mask, desc")
+	g.P("for idx, seg := range segs {")
+	g.P("	if md == nil {")
+	g.P("		return nil, false")
+	g.P("	}")
+	g.P("	fd := md.Fields().ByJSONName(seg)")
+	g.P("	if fd == nil {")
+	g.P("		fd = md.Fields().ByName(", protoreflectPackage.Ident("Name"), "(seg))")
+	g.P("	}")
+	g.P("	if fd == nil {")
+	g.P("		return nil, false")
+	g.P("	}")
+	g.P("	// well known types and maps are not marshaled as messages")
+	g.P("	md = fd.Message()")
+	g.P("	if fd.IsMap() || (md != nil && md.ParentFile().Package() == \"google.protobuf\") {")
+	g.P("		md = nil")
+	g.P("	}")
+	g.P("	if node == nil {")
+	g.P("		continue")
+	g.P("	}")
+	if opts.JSONProtoNames {
+		g.P("	name := string(fd.Name())")
+	} else {
+		g.P("	name := fd.JSONName()")
+	}
+	g.P("	if idx == len(segs)-1 {")
+	g.P("		node[name] = nil")
+	g.P("		continue")
+	g.P("	}")
+	g.P("	next, ok := node[name]")
+	g.P("	if !ok {")
+	g.P("		next = fieldsMask{}")
+	g.P("		node[name] = next")
+	g.P("	}")
+	g.P("	node = next")
+	g.P("}")
+	g.P("}")
+	g.P("return mask, true")
+	g.P("}")
+	g.P("")
+	g.P("func (m fieldsMask) prune(val interface{}) interface{} {")
+	g.P("switch v := val.(type) {")
+	g.P("case map[string]interface{}:")
+	g.P("	for key, sub := range v {")
+	g.P("		mask, ok := m[key]")
+	g.P("		if !ok {")
+	g.P("			delete(v, key)")
+	g.P("		} else if mask != nil {")
+	g.P("			v[key] = mask.prune(sub)")
+	g.P("		}")
+	g.P("	}")
+	g.P("case []interface{}:")
+	g.P("	for idx := range v {")
+	g.P("		v[idx] = m.prune(v[idx])")
+	g.P("	}")
+	g.P("}")
+	g.P("return val")
+	g.P("}")
+	g.P("")
+	g.P("// pruneResponse drops the fields of a marshaled response not in the mask")
+	g.P("func pruneResponse(raw []byte, mask fieldsMask, indent string) ([]byte, error) {")
+	g.P("dec := ", jsonPackage.Ident("NewDecoder"), "(", bytesPackage.Ident("NewReader"), "(raw))")
+	g.P("dec.UseNumber()")
+	g.P("var val interface{}")
+	g.P("if err := dec.Decode(&val); err != nil {")
+	g.P("	return nil, err")
+	g.P("}")
+	g.P("buf := ", bytesPackage.Ident("Buffer"), "{}")
+	g.P("enc := ", jsonPackage.Ident("NewEncoder"), "(&buf)")
+	g.P("enc.SetEscapeHTML(false)")
+	g.P("if indent != \"\" {")
+	g.P("	enc.SetIndent(\"\", indent)")
+	g.P("}")
+	g.P("if err := enc.Encode(mask.prune(val)); err != nil {")
+	g.P("	return nil, err")
+	g.P("}")
+	g.P("return ", bytesPackage.Ident("TrimSuffix"), "(buf.Bytes(), []byte(\"\\n\")), nil")
+	g.P("}")
+	g.P("")
+	g.P("// deriveUpdateMask field paths of the fields present in a json body as a")
+	g.P("// merge patch, explicit nulls are included so that they clear the field and")
+	g.P("// nested messages contribute the paths of their own fields, so an empty")
+	g.P("// object changes nothing")
+	g.P("func deriveUpdateMask(")
+	g.P("raw []byte,")
+	g.P("desc ", protoreflectPackage.Ident("MessageDescriptor"), ",")
+	g.P("prefix string,")
+	g.P("skip ...string,")
+	g.P(") []string {")
+	g.P("fields := map[string]", jsonPackage.Ident("RawMessage"), "{}")
+	g.P("if err := ", jsonPackage.Ident("Unmarshal"), "(raw, &fields); err != nil {")
+	g.P("	return nil")
+	g.P("}")
+	g.P("paths := []string{}")
+	g.P("for key, val := range fields {")
+	g.P("	fd := desc.Fields().ByJSONName(key)")
+	g.P("	if fd == nil {")
+	g.P("		fd = desc.Fields().ByName(", protoreflectPackage.Ident("Name"), "(key))")
+	g.P("	}")
+	g.P("	if fd == nil {")
+	g.P("		continue")
+	g.P("	}")
+	g.P("	skipped := false")
+	g.P("	for idx := range skip {")
+	g.P("		skipped = skipped || (prefix == \"\" && string(fd.Name()) == skip[idx])")
+	g.P("	}")
+	g.P("	if skipped {")
+	g.P("		continue")
+	g.P("	}")
+	g.P("	path := prefix + string(fd.Name())")
+	g.P("	md := fd.Message()")
+	g.P("	if md != nil && !fd.IsList() && !fd.IsMap() &&")
+	g.P("		md.ParentFile().Package() != \"google.protobuf\" &&")
+	g.P("		string(", bytesPackage.Ident("TrimSpace"), "(val)) != \"null\" {")
+	g.P("		paths = append(paths, deriveUpdateMask(val, md, path+\".\")...)")
+	g.P("		continue")
+	g.P("	}")
+	g.P("	paths = append(paths, path)")
+	g.P("}")
+	g.P(sortPackage.Ident("Strings"), "(paths)")
+	g.P("return paths")
+	g.P("}")
+	g.P("")
+	g.P("// parseEnumParameter resolves an enum parameter from its name or number")
+	g.P("func parseEnumParameter(val string, values map[string]int32) (int32, bool) {")
+	g.P("if p, ok := values[val]; ok {")
+	g.P("	return p, true")
+	g.P("}")
+	if opts.EnumCaseInsensitive {
+		g.P("for name, p := range values {")
+		g.P("	if ", stringsPackage.Ident("EqualFold"), "(name, val) {")
+		g.P("		return p, true")
+		g.P("	}")
+		g.P("}")
+	}
+	g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(val, 10, 32)")
+	g.P("if err != nil {")
+	g.P("	return 0, false")
+	g.P("}")
+	g.P("return int32(p), true")
+	g.P("}")
+	g.P("")
+	g.P("// HTTPServerOption configures the generated http servers")
+	g.P("type HTTPServerOption func(*httpServerOptions)")
+	g.P("")
+	g.P("type httpServerOptions struct {")
+	g.P("deprecatedCallHook func(ctx *", ginPackage.Ident("Context"), ", fullMethod string)")
+	g.P("authorizer Authorizer")
+	g.P("featureGate FeatureGate")
+	g.P("disabledFeatureStatus int")
+	g.P("rateLimiter RateLimiter")
+	g.P("rateLimitUserKey func(ctx ", contextPackage.Ident("Context"), ") string")
+	g.P("rateLimitClientIP bool")
+	g.P("idempotencyStore IdempotencyStore")
+	g.P("idempotencyCallerKey func(ctx ", contextPackage.Ident("Context"), ") string")
+	g.P("preconditionFailedError error")
+	g.P("jsonResolver JSONResolver")
+	g.P("contextFactory ContextFactory")
+	g.P("serverFieldPopulator ServerFieldPopulator")
+	g.P("unaryInterceptors []", grpcPackage.Ident("UnaryServerInterceptor"))
 	for _, srv := range srvs {
-		intname := srv.Service.GoName + "HTTPServer"
-		g.P(fmt.Sprintf("// %s", srv.Service.GoName))
-		g.P("type ", intname, " interface {")
-		for _, rpc := range srv.Paths {
-			// inputStructName := rpc.Method.Input.GoIdent.GoName
-			// if _, ok := imports[rpc.Method.Input.GoIdent.GoImportPath]; ok {
-			// 	inputStructName = rpc.Method.Input.GoIdent.GoImportPath.Ident(inputStructName)
-			// }
-			g.Write([]byte(rpc.Method.Comments.Leading.String()))
-			g.P(
-				"\t",
-				rpc.Method.GoName,
-				"(",
-				contextPackage.Ident("Context"),
-				", *",
-				rpc.Method.Input.GoIdent,
-				") (*",
-				rpc.Method.Output.GoIdent,
-				", error)",
-			)
-			g.Write([]byte(rpc.Method.Comments.Trailing.String()))
+		if srv.CORS != nil {
+			g.P("corsPreflights *", syncPackage.Ident("Map"))
+			break
 		}
-		g.P("}")
-
-		// controllers
-		// TODO: handle path and query parameter type :)
-		ctrlName := ToPrivateName(srv.Service.GoName)
-		g.P("type ", ctrlName, " struct {")
-		g.P("app ", intname)
-		g.P("}")
-
-		for _, rpc := range srv.Paths {
-
-			g.P("// ", rpc.Description)
-			g.P(
-				"func (p *",
-				ctrlName,
-				")",
-				ToPrivateName(rpc.Method.GoName),
-				"(ctx *",
-				ginPackage.Ident("Context"),
-				") {",
-			)
-
-			g.P("body := ", rpc.Method.Input.GoIdent, "{}")
-			if rpc.HTTPMethod != "GET" && rpc.HTTPMethod != "DELETE" {
-				// TODO if anything left in body
-				g.P("raw, err :=", ioutilPackage.Ident("ReadAll"), "(ctx.Request.Body)")
-				g.P("if err != nil {")
-				g.P("	ctx.Error(err)")
-				g.P("	return")
-				g.P("}")
-				g.P(protojsonPackage.Ident("Unmarshal"), "(raw, &body)")
-			} else {
-				renderQueryParameters(g, rpc.Parameters, []string{})
-			}
-			renderPathParameters(g, rpc.Parameters, []string{})
-
-			// for _, qpm := range rpc.QueryParameters {
-			// 	g.P("body.", qpm.ModelParameter, "= ctx.Query(\",", qpm.Key, "\")")
-			// }
-			// for _, pth := range rpc.PathParameters {
-			// 	g.P("body.", pth.ModelParameter, "= ctx.Param(\",", pth.Key, "\")")
-			// }
-
-			g.P("var c ", contextPackage.Ident("Context"))
-			g.P("if v, ok := ctx.Get(InternalContextKey); ok {")
-			g.P("	c, _ = v.(", contextPackage.Ident("Context"), ")")
-			g.P("}")
-			g.P("if c == nil {")
-			g.P("	c = ctx")
-			g.P("}")
+	}
+	if opts.OTel {
+		g.P("tracerProvider ", oteltracePackage.Ident("TracerProvider"))
+		g.P("meterProvider ", otelmetPackage.Ident("MeterProvider"))
+		g.P("propagator ", otelpropPackage.Ident("TextMapPropagator"))
+	}
+	g.P("}")
+	g.P("")
+	g.P("// WithDeprecatedCallHook sets a hook called every time a deprecated rpc is")
+	g.P("// invoked, useful for tracking usage of deprecated endpoints")
+	g.P("func WithDeprecatedCallHook(")
+	g.P("hook func(ctx *", ginPackage.Ident("Context"), ", fullMethod string),")
+	g.P(") HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.deprecatedCallHook = hook")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// WithAuthorizer sets the authorizer consulted before dispatching calls to")
+	g.P("// rpcs that are not marked anonymous, without one those calls are denied")
+	g.P("// with a 403 error")
+	g.P("func WithAuthorizer(authorizer Authorizer) HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.authorizer = authorizer")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// WithPreconditionFailedError sets the domain error returned by commands when")
+	g.P("// the version populated from If-Match is stale, it responds with 412")
+	g.P("func WithPreconditionFailedError(err error) HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.preconditionFailedError = err")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// ContextFactory creates the context rpcs are invoked with, the request is")
+	g.P("// available through the gin context, errors are passed to the gin context")
+	g.P("// and abort the call")
+	g.P("type ContextFactory interface {")
+	g.P("NewContext(")
+	g.P("ctx *", ginPackage.Ident("Context"), ",")
+	g.P("info *HTTPRouteInfo,")
+	g.P(") (", contextPackage.Ident("Context"), ", error)")
+	g.P("}")
+	g.P("")
+	g.P("// ContextFactoryFunc function implementing ContextFactory")
+	g.P("type ContextFactoryFunc func(")
+	g.P("ctx *", ginPackage.Ident("Context"), ",")
+	g.P("info *HTTPRouteInfo,")
+	g.P(") (", contextPackage.Ident("Context"), ", error)")
+	g.P("")
+	g.P("// NewContext calls the function")
+	g.P("func (f ContextFactoryFunc) NewContext(")
+	g.P("ctx *", ginPackage.Ident("Context"), ",")
+	g.P("info *HTTPRouteInfo,")
+	g.P(") (", contextPackage.Ident("Context"), ", error) {")
+	g.P("return f(ctx, info)")
+	g.P("}")
+	g.P("")
+	g.P("// WithContextFactory sets the factory creating the context rpcs are invoked")
+	g.P("// with, defaults to the context stored under InternalContextKey or the gin")
+	g.P("// context itself")
+	g.P("func WithContextFactory(factory ContextFactory) HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.contextFactory = factory")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("type internalContextFactory struct{}")
+	g.P("")
+	g.P("func (internalContextFactory) NewContext(")
+	g.P("ctx *", ginPackage.Ident("Context"), ",")
+	g.P("_ *HTTPRouteInfo,")
+	g.P(") (", contextPackage.Ident("Context"), ", error) {")
+	g.P("if v, ok := ctx.Get(InternalContextKey); ok {")
+	g.P("	if c, ok := v.(", contextPackage.Ident("Context"), "); ok && c != nil {")
+	g.P("		return c, nil")
+	g.P("	}")
+	g.P("}")
+	g.P("return ctx, nil")
+	g.P("}")
+	g.P("")
+	g.P("// ServerFieldPopulator fills in the fields of inputs marked as server")
+	g.P("// populated, ex. from the claims of the caller, after any value sent by the")
+	g.P("// client has been cleared")
+	g.P("type ServerFieldPopulator interface {")
+	g.P("PopulateServerFields(")
+	g.P("ctx ", contextPackage.Ident("Context"), ",")
+	g.P("info *HTTPRouteInfo,")
+	g.P("input ", protoPackage.Ident("Message"), ",")
+	g.P("fields []", protoreflectPackage.Ident("FieldDescriptor"), ",")
+	g.P(") error")
+	g.P("}")
+	g.P("")
+	g.P("// ServerFieldPopulatorFunc function implementing ServerFieldPopulator")
+	g.P("type ServerFieldPopulatorFunc func(")
+	g.P("ctx ", contextPackage.Ident("Context"), ",")
+	g.P("info *HTTPRouteInfo,")
+	g.P("input ", protoPackage.Ident("Message"), ",")
+	g.P("fields []", protoreflectPackage.Ident("FieldDescriptor"), ",")
+	g.P(") error")
+	g.P("")
+	g.P("// PopulateServerFields calls the function")
+	g.P("func (f ServerFieldPopulatorFunc) PopulateServerFields(")
+	g.P("ctx ", contextPackage.Ident("Context"), ",")
+	g.P("info *HTTPRouteInfo,")
+	g.P("input ", protoPackage.Ident("Message"), ",")
+	g.P("fields []", protoreflectPackage.Ident("FieldDescriptor"), ",")
+	g.P(") error {")
+	g.P("return f(ctx, info, input, fields)")
+	g.P("}")
+	g.P("")
+	g.P("// WithServerFieldPopulator sets the populator filling in server populated")
+	g.P("// fields, without one they are only cleared")
+	g.P("func WithServerFieldPopulator(populator ServerFieldPopulator) HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.serverFieldPopulator = populator")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// WithUnaryInterceptors adds interceptors run around every rpc after its")
+	g.P("// input is bound, the first one being the outermost. grpc interceptors can be")
+	g.P("// used as is, they get the full method of the rpc in the server info")
+	g.P("func WithUnaryInterceptors(")
+	g.P("interceptors ...", grpcPackage.Ident("UnaryServerInterceptor"), ",")
+	g.P(") HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.unaryInterceptors = append(o.unaryInterceptors, interceptors...)")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// invokeUnary calls the handler through the interceptors")
+	g.P("func invokeUnary(")
+	g.P("ctx ", contextPackage.Ident("Context"), ",")
+	g.P("req interface{},")
+	g.P("info *", grpcPackage.Ident("UnaryServerInfo"), ",")
+	g.P("interceptors []", grpcPackage.Ident("UnaryServerInterceptor"), ",")
+	g.P("handler ", grpcPackage.Ident("UnaryHandler"), ",")
+	g.P(") (interface{}, error) {")
+	g.P("if len(interceptors) == 0 {")
+	g.P("	return handler(ctx, req)")
+	g.P("}")
+	g.P("return interceptors[0](")
+	g.P("ctx,")
+	g.P("req,")
+	g.P("info,")
+	g.P("func(ctx ", contextPackage.Ident("Context"), ", req interface{}) (interface{}, error) {")
+	g.P("	return invokeUnary(ctx, req, info, interceptors[1:], handler)")
+	g.P("},")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("// incomingContext adds the request headers to the context as incoming grpc")
+	g.P("// metadata, so that interceptors written for grpc servers can read them")
+	g.P("func incomingContext(")
+	g.P("c ", contextPackage.Ident("Context"), ",")
+	g.P("header ", nethttpPackage.Ident("Header"), ",")
+	g.P(") ", contextPackage.Ident("Context"), " {")
+	g.P("md := ", metadataPackage.Ident("MD"), "{}")
+	g.P("for key, vals := range header {")
+	g.P("	key = ", stringsPackage.Ident("ToLower"), "(key)")
+	g.P("	if !", stringsPackage.Ident("HasSuffix"), "(key, \"-bin\") {")
+	g.P("		md.Append(key, vals...)")
+	g.P("		continue")
+	g.P("	}")
+	g.P("	// binary metadata is sent base64 encoded")
+	g.P("	for _, val := range vals {")
+	g.P("		if raw, err := decodeBytesParameter(val); err == nil {")
+	g.P("			md.Append(key, string(raw))")
+	g.P("		}")
+	g.P("	}")
+	g.P("}")
+	g.P("if found, ok := ", metadataPackage.Ident("FromIncomingContext"), "(c); ok {")
+	g.P("	md = ", metadataPackage.Ident("Join"), "(found, md)")
+	g.P("}")
+	g.P("return ", metadataPackage.Ident("NewIncomingContext"), "(c, md)")
+	g.P("}")
+	g.P("")
+	g.P("// httpStatusCodes http status matching each grpc status code")
+	g.P("var httpStatusCodes = map[", codesPackage.Ident("Code"), "]int{")
+	g.P("	", codesPackage.Ident("Canceled"), ": 499,")
+	g.P("	", codesPackage.Ident("Unknown"), ": 500,")
+	g.P("	", codesPackage.Ident("InvalidArgument"), ": 400,")
+	g.P("	", codesPackage.Ident("DeadlineExceeded"), ": 504,")
+	g.P("	", codesPackage.Ident("NotFound"), ": 404,")
+	g.P("	", codesPackage.Ident("AlreadyExists"), ": 409,")
+	g.P("	", codesPackage.Ident("PermissionDenied"), ": 403,")
+	g.P("	", codesPackage.Ident("ResourceExhausted"), ": 429,")
+	g.P("	", codesPackage.Ident("FailedPrecondition"), ": 400,")
+	g.P("	", codesPackage.Ident("Aborted"), ": 409,")
+	g.P("	", codesPackage.Ident("OutOfRange"), ": 400,")
+	g.P("	", codesPackage.Ident("Unimplemented"), ": 501,")
+	g.P("	", codesPackage.Ident("Internal"), ": 500,")
+	g.P("	", codesPackage.Ident("Unavailable"), ": 503,")
+	g.P("	", codesPackage.Ident("DataLoss"), ": 500,")
+	g.P("	", codesPackage.Ident("Unauthenticated"), ": 401,")
+	g.P("}")
+	g.P("")
+	g.P("// statusError maps grpc status errors, ex. returned by interceptors, to an")
+	g.P("// error with the matching http status, other errors are returned unchanged")
+	g.P("func statusError(err error) error {")
+	g.P("st, ok := ", statusPackage.Ident("FromError"), "(err)")
+	g.P("if !ok {")
+	g.P("	return err")
+	g.P("}")
+	g.P("code, ok := httpStatusCodes[st.Code()]")
+	g.P("if !ok {")
+	g.P("	code = 500")
+	g.P("}")
+	g.P("return ", gorrPackage.Ident("NewError"), "(")
+	g.P(gorrPackage.Ident("ErrorCode"), "{")
+	g.P("		Code:    code,")
+	g.P("		Message: st.Code().String() + \"Error\",")
+	g.P("	},")
+	g.P("	code,")
+	g.P("	st.Message(),")
+	g.P(")")
+	g.P("}")
+	g.P("")
+	g.P("// JSONResolver resolves the types of Any fields and extensions when")
+	g.P("// marshaling responses and unmarshaling requests")
+	g.P("type JSONResolver interface {")
+	g.P(protoregPackage.Ident("ExtensionTypeResolver"))
+	g.P(protoregPackage.Ident("MessageTypeResolver"))
+	g.P("}")
+	g.P("")
+	g.P("// WithJSONResolver sets the resolver used for Any fields and extensions,")
+	g.P("// defaults to the global registry")
+	g.P("func WithJSONResolver(resolver JSONResolver) HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.jsonResolver = resolver")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// HTTPRouteInfo describes an rpc exposed by the generated http servers")
+	g.P("type HTTPRouteInfo struct {")
+	g.P("Service    string")
+	g.P("FullMethod string")
+	g.P("HTTPMethod string")
+	g.P("Path       string")
+	g.P("Roles      []PermissionRole")
+	g.P("Features   []PermissionFeature")
+	g.P("Tags       []string")
+	g.P("Anonymous  bool")
+	g.P("RateLimit  *RateLimit")
+	g.P("// Timeout longest the rpc may run for, 0 when it has no limit")
+	g.P("Timeout ", timePackage.Ident("Duration"))
+	g.P("}")
+	g.P("")
+	g.P("// WithFeatureGate sets the gate consulted for the features of an rpc before")
+	g.P("// it is invoked, calls to rpcs behind a disabled feature respond with the")
+	g.P("// disabled status, either 404 or 403")
+	g.P("func WithFeatureGate(gate FeatureGate, disabledStatus int) HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.featureGate = gate")
+	g.P("	o.disabledFeatureStatus = disabledStatus")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// FeatureGate decides if a feature is enabled for a request")
+	g.P("type FeatureGate interface {")
+	g.P(
+		"IsEnabled(ctx ",
+		contextPackage.Ident("Context"),
+		", feature PermissionFeature) (bool, error)",
+	)
+	g.P("}")
+	g.P("")
+	g.P("// ResourceAction action performed on a resource, commands write while")
+	g.P("// queries read")
+	g.P("type ResourceAction string")
+	g.P("")
+	g.P("const (")
+	g.P("ResourceActionRead  ResourceAction = \"read\"")
+	g.P("ResourceActionWrite ResourceAction = \"write\"")
+	g.P(")")
+	g.P("")
+	g.P("// Authorizer authorizes calls to rpcs against the roles and features")
+	g.P("// declared in their documentation, returning false denies the call with a")
+	g.P("// 403 error. Rpcs that are not marked anonymous are denied when no")
+	g.P("// authorizer is set")
+	g.P("type Authorizer interface {")
+	g.P("Authorize(")
+	g.P("ctx ", contextPackage.Ident("Context"), ",")
+	g.P("info *HTTPRouteInfo,")
+	g.P("input ", protoPackage.Ident("Message"), ",")
+	g.P(") (bool, error)")
+	g.P("// CheckResource checks access to a resource identified by a field marked")
+	g.P("// with a resource type, called after Authorize")
+	g.P("CheckResource(")
+	g.P("ctx ", contextPackage.Ident("Context"), ",")
+	g.P("resourceType string,")
+	g.P("id string,")
+	g.P("action ResourceAction,")
+	g.P(") (bool, error)")
+	g.P("}")
+	g.P("")
+	renderRateLimiter(g)
+	renderIdempotencyStore(g)
+	if opts.OTel {
+		renderTelemetry(g)
+	}
+	for _, srv := range srvs {
+		if srv.CORS != nil {
+			renderCORSPolicy(g)
+			break
+		}
+	}
+
+	for _, srv := range srvs {
+		intname := srv.Service.GoName + "HTTPServer"
+		g.P(fmt.Sprintf("// %s", srv.Service.GoName))
+		g.P("type ", intname, " interface {")
+		for _, rpc := range srv.Paths {
+			// inputStructName := rpc.Method.Input.GoIdent.GoName
+			// if _, ok := imports[rpc.Method.Input.GoIdent.GoImportPath]; ok {
+			// 	inputStructName = rpc.Method.Input.GoIdent.GoImportPath.Ident(inputStructName)
+			// }
+			g.Write([]byte(rpc.Method.Comments.Leading.String()))
+			g.P(
+				"\t",
+				rpc.Method.GoName,
+				"(",
+				contextPackage.Ident("Context"),
+				", *",
+				rpc.Method.Input.GoIdent,
+				") (*",
+				rpc.Method.Output.GoIdent,
+				", error)",
+			)
+			g.Write([]byte(rpc.Method.Comments.Trailing.String()))
+		}
+		g.P("}")
+
+		// controllers
+		// TODO: handle path and query parameter type :)
+		ctrlName := ToPrivateName(srv.Service.GoName)
+		if srv.CORS != nil {
+			renderServerCORSPolicy(g, srv)
+		}
+		g.P("type ", ctrlName, " struct {")
+		g.P("app ", intname)
+		g.P("opts httpServerOptions")
+		if opts.OTel {
+			g.P("telemetry *httpTelemetry")
+		}
+		g.P("}")
+
+		for _, rpc := range srv.Paths {
+			renderRouteInfo(g, rpc)
+			if rpc.JSON != nil {
+				g.P(
+					"var ",
+					rpc.MarshalOptionsName(),
+					" = ",
+					protojsonPackage.Ident("MarshalOptions"),
+					"{",
+				)
+				g.P("UseProtoNames: ", opts.JSONProtoNames, ",")
+				g.P("UseEnumNumbers: ", rpc.JSON.EnumNumbers, ",")
+				g.P("EmitUnpopulated: ", rpc.JSON.EmitUnpopulated, ",")
+				g.P("Indent: ", strconv.Quote(rpc.JSON.Indent), ",")
+				g.P("}")
+				g.P("")
+			}
+
+			g.P("// ", rpc.Description)
+			g.P(
+				"func (p *",
+				ctrlName,
+				")",
+				ToPrivateName(rpc.Method.GoName),
+				"(ctx *",
+				ginPackage.Ident("Context"),
+				") {",
+			)
 
-			g.P("res, err := p.app.", rpc.Method.GoName, "(")
-			g.P("c,")
+			if opts.OTel {
+				g.P("start := ", timePackage.Ident("Now"), "()")
+				g.P("span := p.telemetry.start(ctx, ", rpc.RouteInfoName(), ")")
+				g.P("defer p.telemetry.end(ctx, ", rpc.RouteInfoName(), ", span, start)")
+			}
+
+			if rpc.Deprecation.Deprecated {
+				renderDeprecationHeaders(g, rpc)
+			}
+
+			g.P("c, err := p.opts.contextFactory.NewContext(ctx, ", rpc.RouteInfoName(), ")")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(err)")
+			g.P("	return")
+			g.P("}")
+			if opts.OTel {
+				g.P("c = ", oteltracePackage.Ident("ContextWithSpan"), "(c, span)")
+			}
+
+			if rpc.RateLimit != nil {
+				renderRateLimitCheck(g, rpc)
+			}
+
+			if len(rpc.Features) != 0 {
+				g.P("if p.opts.featureGate != nil {")
+				g.P("for _, feature := range ", rpc.RouteInfoName(), ".Features {")
+				g.P("enabled, err := p.opts.featureGate.IsEnabled(c, feature)")
+				g.P("if err != nil {")
+				g.P("	ctx.Error(err)")
+				g.P("	return")
+				g.P("}")
+				g.P("if !enabled {")
+				g.P("	ctx.Error(newFeatureDisabledError(p.opts.disabledFeatureStatus))")
+				g.P("	return")
+				g.P("}")
+				g.P("}")
+				g.P("}")
+			}
+
+			g.P("body := ", rpc.Method.Input.GoIdent, "{}")
+			if rpc.Body != nil {
+				renderRawBody(g, rpc, opts)
+			} else if rpc.HTTPMethod != "GET" && rpc.HTTPMethod != "DELETE" {
+				// TODO if anything left in body
+				g.P("raw, err :=", ioutilPackage.Ident("ReadAll"), "(ctx.Request.Body)")
+				g.P("if err != nil {")
+				g.P("	ctx.Error(err)")
+				g.P("	return")
+				g.P("}")
+				g.P("unmarsh := ", protojsonPackage.Ident("UnmarshalOptions"), "{}")
+				g.P("if p.opts.jsonResolver != nil {")
+				g.P("	unmarsh.Resolver = p.opts.jsonResolver")
+				g.P("}")
+				if rpc.UpdateMask != nil {
+					// the mask is derived from the raw body, so it has to be
+					// the body that was bound
+					g.P("if err := unmarsh.Unmarshal(raw, &body); err != nil {")
+					g.P("	ctx.Error(newUnparsableParameterError(\"body\"))")
+					g.P("	return")
+					g.P("}")
+				} else {
+					g.P("unmarsh.Unmarshal(raw, &body)")
+				}
+				if rpc.UpdateMask != nil {
+					g.P("if body.", rpc.UpdateMask.GoName, " == nil {")
+					g.P(
+						"body.",
+						rpc.UpdateMask.GoName,
+						" = &",
+						fieldmaskPackage.Ident("FieldMask"),
+						"{",
+					)
+					g.P("Paths: deriveUpdateMask(")
+					g.P("raw,")
+					g.P("body.ProtoReflect().Descriptor(),")
+					g.P("\"\",")
+					g.P(strconv.Quote(string(rpc.UpdateMask.Desc.Name())), ",")
+					for _, field := range rpc.ServerFields() {
+						g.P(strconv.Quote(string(field.Desc.Name())), ",")
+					}
+					g.P("),")
+					g.P("}")
+					g.P("}")
+				}
+			} else {
+				renderQueryParameters(g, rpc.Parameters, []string{}, "ctx", "Query", opts)
+			}
+			renderPathParameters(g, rpc.Parameters, []string{}, opts)
+			renderHeaderParameters(g, rpc.Parameters, opts)
+			if len(rpc.ServerFields()) != 0 {
+				renderServerFields(g, rpc)
+			}
+			if rpc.Concurrency != nil {
+				renderIfMatch(g, rpc)
+			}
+			if rpc.Pagination != nil {
+				renderPageSizeCheck(g, rpc, opts)
+			}
+			if rpc.HasFieldsParameter() {
+				g.P("fields, ok := parseFieldsParameter(")
+				g.P("ctx.Query(\"fields\"),")
+				g.P("(*", rpc.Method.Output.GoIdent, ")(nil).ProtoReflect().Descriptor(),")
+				g.P(")")
+				g.P("if !ok {")
+				g.P("	ctx.Error(newUnparsableParameterError(\"fields\"))")
+				g.P("	return")
+				g.P("}")
+			}
+
+			// for _, qpm := range rpc.QueryParameters {
+			// 	g.P("body.", qpm.ModelParameter, "= ctx.Query(\",", qpm.Key, "\")")
+			// }
+			// for _, pth := range rpc.PathParameters {
+			// 	g.P("body.", pth.ModelParameter, "= ctx.Param(\",", pth.Key, "\")")
+			// }
+
+			if !rpc.Anonymous {
+				g.P("if p.opts.authorizer == nil {")
+				g.P("	ctx.Error(newForbiddenError())")
+				g.P("	return")
+				g.P("}")
+				g.P(
+					"authorized, err := p.opts.authorizer.Authorize(c, ",
+					rpc.RouteInfoName(),
+					", &body)",
+				)
+				g.P("if err != nil {")
+				g.P("	ctx.Error(err)")
+				g.P("	return")
+				g.P("}")
+				g.P("if !authorized {")
+				g.P("	ctx.Error(newForbiddenError())")
+				g.P("	return")
+				g.P("}")
+				renderResourceChecks(g, rpc)
+			}
+
+			if rpc.Idempotent {
+				renderIdempotencyBegin(g, rpc)
+			}
+
+			g.P("invokeCtx := incomingContext(c, ctx.Request.Header)")
+			if rpc.Timeout != nil {
+				renderTimeout(g, rpc)
+			}
+			g.P("out, err := invokeUnary(")
+			g.P("invokeCtx,")
 			g.P("&body,")
+			g.P("&", grpcPackage.Ident("UnaryServerInfo"), "{")
+			g.P("Server: p.app,")
+			g.P("FullMethod: ", rpc.RouteInfoName(), ".FullMethod,")
+			g.P("},")
+			g.P("p.opts.unaryInterceptors,")
+			g.P(
+				"func(c ",
+				contextPackage.Ident("Context"),
+				", req interface{}) (interface{}, error) {",
+			)
+			g.P(
+				"	return p.app.",
+				rpc.Method.GoName,
+				"(c, req.(*",
+				rpc.Method.Input.GoIdent,
+				"))",
+			)
+			g.P("},")
 			g.P(")")
 			g.P("if err != nil {")
-			g.P("ctx.Error(err)")
+			if rpc.Timeout != nil {
+				g.P(
+					"if ",
+					errorsPackage.Ident("Is"),
+					"(err, ",
+					contextPackage.Ident("DeadlineExceeded"),
+					") ||",
+				)
+				g.P(
+					"	",
+					errorsPackage.Ident("Is"),
+					"(invokeCtx.Err(), ",
+					contextPackage.Ident("DeadlineExceeded"),
+					") {",
+				)
+				g.P("	ctx.Error(newGatewayTimeoutError())")
+				g.P("	return")
+				g.P("}")
+			}
+			if rpc.Concurrency != nil {
+				g.P("if p.opts.preconditionFailedError != nil &&")
+				g.P("	", errorsPackage.Ident("Is"), "(err, p.opts.preconditionFailedError) {")
+				g.P("	ctx.Error(newPreconditionFailedError())")
+				g.P("	return")
+				g.P("}")
+			}
+			g.P("ctx.Error(statusError(err))")
 			g.P("return")
 			g.P("}")
 
-			g.P("resraw, err := protomarsh.Marshal(res)")
-			g.P("if err != nil {")
-			g.P("	ctx.Error(err)")
+			g.P("res, ok := out.(*", rpc.Method.Output.GoIdent, ")")
+			g.P("if !ok {")
+			g.P(
+				"	ctx.Error(",
+				fmtPackage.Ident("Errorf"),
+				"(\"unexpected response type %T\", out))",
+			)
 			g.P("	return")
 			g.P("}")
+
+			if rpc.RawResponse != nil {
+				g.P("resraw := res.Get", rpc.RawResponse.Field.GoName, "()")
+			} else {
+				g.P("marsh := ", rpc.MarshalOptionsName())
+				g.P("if p.opts.jsonResolver != nil {")
+				g.P("	marsh.Resolver = p.opts.jsonResolver")
+				g.P("}")
+				g.P("resraw, err := marsh.Marshal(res)")
+				g.P("if err != nil {")
+				g.P("	ctx.Error(err)")
+				g.P("	return")
+				g.P("}")
+			}
+			if rpc.HasFieldsParameter() {
+				g.P("if fields != nil {")
+				g.P("resraw, err = pruneResponse(resraw, fields, marsh.Indent)")
+				g.P("if err != nil {")
+				g.P("	ctx.Error(err)")
+				g.P("	return")
+				g.P("}")
+				g.P("}")
+			}
+			if rpc.Pagination != nil && rpc.HTTPMethod == "GET" {
+				g.P(
+					"if token := res.Get",
+					rpc.Pagination.NextPageToken.GoName,
+					"(); token != \"\" {",
+				)
+				g.P(
+					"	ctx.Header(\"Link\", nextPageLink(ctx, \"",
+					opts.FieldName(rpc.Pagination.PageToken.Desc),
+					"\", token))",
+				)
+				g.P("}")
+			}
+			if rpc.Cache != nil {
+				renderCacheHeaders(g, rpc)
+			}
+			if rpc.Concurrency != nil && rpc.Concurrency.ResponseField != nil {
+				g.P(
+					"ctx.Header(\"ETag\", versionETag(",
+					formatField(g, rpc.Concurrency.ResponseField, "res"),
+					"))",
+				)
+			}
 			g.P("ctx.Status(200)")
-			g.P("ctx.Header(\"Content-Type\", \"application/json\")")
+			if rpc.RawResponse != nil {
+				renderRawResponseHeaders(g, rpc)
+			} else {
+				g.P("ctx.Header(\"Content-Type\", \"application/json\")")
+			}
+			if rpc.Idempotent {
+				g.P("if idempotencyKey != \"\" {")
+				g.P("err = p.opts.idempotencyStore.Complete(")
+				g.P("c,")
+				g.P("idempotencyKey,")
+				g.P("newIdempotencyResponse(ctx, resraw),")
+				g.P(")")
+				g.P("if err != nil {")
+				g.P("	ctx.Error(err)")
+				g.P("	return")
+				g.P("}")
+				g.P("idempotencyCompleted = true")
+				g.P("}")
+			}
 			g.P("_, err = ctx.Writer.Write(resraw)")
 			g.P("if err != nil {")
 			g.P("	ctx.Error(err)")
@@ -157,33 +1339,1422 @@ func GenerateHTTPServers(
 			g.P("}")
 		}
 
-		g.P("func Register", srv.Service.GoName, "HTTPServer (")
-		g.P("grp *", ginPackage.Ident("RouterGroup"), ",")
-		g.P("srv ", intname, ",")
-		g.P(") {")
-		g.P("ctrl := ", ctrlName, "{app: srv}")
-		for _, rpc := range srv.Paths {
-			g.P(
-				"grp.",
-				rpc.HTTPMethod,
-				"(\"",
-				rpc.GoPath,
-				"\", ",
-				"ctrl.",
-				ToPrivateName(rpc.Method.GoName),
-				")",
-			)
+		g.P("func Register", srv.Service.GoName, "HTTPServer (")
+		g.P("grp *", ginPackage.Ident("RouterGroup"), ",")
+		g.P("srv ", intname, ",")
+		g.P("opts ...HTTPServerOption,")
+		g.P(") {")
+		g.P("ctrl := ", ctrlName, "{app: srv}")
+		g.P("for _, opt := range opts {")
+		g.P("	opt(&ctrl.opts)")
+		g.P("}")
+		g.P("if ctrl.opts.contextFactory == nil {")
+		g.P("	ctrl.opts.contextFactory = internalContextFactory{}")
+		g.P("}")
+		if opts.OTel {
+			g.P("ctrl.telemetry = newHTTPTelemetry(ctrl.opts)")
+		}
+		for _, rpc := range srv.Paths {
+			if rpc.RateLimit != nil {
+				g.P("if ctrl.opts.rateLimiter == nil {")
+				g.P("	ctrl.opts.rateLimiter = NewMemoryRateLimiter()")
+				g.P("}")
+				break
+			}
+		}
+		for _, rpc := range srv.Paths {
+			if rpc.Idempotent {
+				g.P("if ctrl.opts.idempotencyStore == nil {")
+				g.P(
+					"	ctrl.opts.idempotencyStore = NewMemoryIdempotencyStore(24 * ",
+					timePackage.Ident("Hour"),
+					")",
+				)
+				g.P("}")
+				break
+			}
+		}
+		cors := ""
+		if srv.CORS != nil {
+			cors = srv.CORSPolicyName() + ".handle, "
+			g.P("if ctrl.opts.corsPreflights == nil {")
+			g.P("	ctrl.opts.corsPreflights = &", syncPackage.Ident("Map"), "{}")
+			g.P("}")
+			routes, methods := srv.RouteMethods()
+			for _, route := range routes {
+				g.P("registerCORSPreflight(")
+				g.P("grp,")
+				g.P("ctrl.opts.corsPreflights,")
+				g.P("\"", route, "\",")
+				g.P(srv.CORSPolicyName(), ".preflight(", goStringSlice(methods[route]), "),")
+				g.P(")")
+			}
+		}
+		for _, rpc := range srv.Paths {
+			g.P(
+				"grp.",
+				rpc.HTTPMethod,
+				"(\"",
+				rpc.GoPath,
+				"\", ",
+				cors,
+				"ctrl.",
+				ToPrivateName(rpc.Method.GoName),
+				")",
+			)
+		}
+		g.P("}")
+	}
+	renderStreamedFieldReaders(g, srvs)
+
+	return nil
+}
+
+func renderCORSPolicy(g *protogen.GeneratedFile) {
+	g.P("// httpCORSPolicy cross origin policy of a service")
+	g.P("type httpCORSPolicy struct {")
+	g.P("// origins allowed origins, * allows any")
+	g.P("origins []string")
+	g.P("headers string")
+	g.P("// anyHeader allows any header requested in preflight requests")
+	g.P("anyHeader   bool")
+	g.P("exposed     string")
+	g.P("credentials bool")
+	g.P("maxAge      string")
+	g.P("}")
+	g.P("")
+	g.P("// allows checks if calls from the origin are allowed")
+	g.P("func (p *httpCORSPolicy) allows(origin string) bool {")
+	g.P("for _, allowed := range p.origins {")
+	g.P("	if allowed == \"*\" || ", stringsPackage.Ident("EqualFold"), "(allowed, origin) {")
+	g.P("		return true")
+	g.P("	}")
+	g.P("}")
+	g.P("return false")
+	g.P("}")
+	g.P("")
+	g.P("// allowOrigin sets the allowed origin of cross origin calls from an allowed")
+	g.P("// origin")
+	g.P(
+		"func (p *httpCORSPolicy) allowOrigin(ctx *",
+		ginPackage.Ident("Context"),
+		", origin string) {",
+	)
+	g.P("for _, allowed := range p.origins {")
+	g.P("	if allowed == \"*\" {")
+	g.P("		ctx.Header(\"Access-Control-Allow-Origin\", \"*\")")
+	g.P("		return")
+	g.P("	}")
+	g.P("}")
+	g.P("ctx.Header(\"Access-Control-Allow-Origin\", origin)")
+	g.P("if p.credentials {")
+	g.P("	ctx.Header(\"Access-Control-Allow-Credentials\", \"true\")")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// handle sets the cors headers of cross origin calls")
+	g.P("func (p *httpCORSPolicy) handle(ctx *", ginPackage.Ident("Context"), ") {")
+	g.P("origin := ctx.GetHeader(\"Origin\")")
+	g.P("if origin == \"\" {")
+	g.P("	return")
+	g.P("}")
+	g.P("ctx.Writer.Header().Add(\"Vary\", \"Origin\")")
+	g.P("if !p.allows(origin) {")
+	g.P("	return")
+	g.P("}")
+	g.P("p.allowOrigin(ctx, origin)")
+	g.P("if p.exposed != \"\" {")
+	g.P("	ctx.Header(\"Access-Control-Expose-Headers\", p.exposed)")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// preflight answers the preflight requests of the methods the service")
+	g.P("// registered on a route, adding them to the ones allowed by the services")
+	g.P("// answering before it. The first service whose policy allows the origin")
+	g.P("// answers for the origin and headers")
+	g.P(
+		"func (p *httpCORSPolicy) preflight(methods []string) ",
+		ginPackage.Ident("HandlerFunc"),
+		" {",
+	)
+	g.P("return func(ctx *", ginPackage.Ident("Context"), ") {")
+	g.P("hdr := ctx.Writer.Header()")
+	g.P("joined := ", stringsPackage.Ident("Join"), "(methods, \", \")")
+	g.P("if allow := hdr.Get(\"Allow\"); allow != \"\" {")
+	g.P("	hdr.Set(\"Allow\", allow+\", \"+joined)")
+	g.P("} else {")
+	g.P("	hdr.Set(\"Allow\", \"OPTIONS, \"+joined)")
+	g.P("}")
+	g.P("origin := ctx.GetHeader(\"Origin\")")
+	g.P("if origin == \"\" {")
+	g.P("	return")
+	g.P("}")
+	g.P("hdr.Set(\"Vary\", \"Origin\")")
+	g.P("if !p.allows(origin) {")
+	g.P("	return")
+	g.P("}")
+	g.P("if allowed := hdr.Get(\"Access-Control-Allow-Methods\"); allowed != \"\" {")
+	g.P("	hdr.Set(\"Access-Control-Allow-Methods\", allowed+\", \"+joined)")
+	g.P("} else {")
+	g.P("	hdr.Set(\"Access-Control-Allow-Methods\", joined)")
+	g.P("}")
+	g.P("if hdr.Get(\"Access-Control-Allow-Origin\") != \"\" {")
+	g.P("	return")
+	g.P("}")
+	g.P("p.allowOrigin(ctx, origin)")
+	g.P("headers := p.headers")
+	g.P(
+		"if req := ctx.GetHeader(\"Access-Control-Request-Headers\"); p.anyHeader && req != \"\" {",
+	)
+	g.P("	headers = req")
+	g.P("}")
+	g.P("hdr.Set(\"Access-Control-Allow-Headers\", headers)")
+	g.P("if p.maxAge != \"\" {")
+	g.P("	hdr.Set(\"Access-Control-Max-Age\", p.maxAge)")
+	g.P("}")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// WithCORSPreflights sets the map the preflight handlers of the routes are")
+	g.P("// kept in, gin allows a single OPTIONS handler per route so servers sharing")
+	g.P("// a route add their preflight handlers to the one registered first. Servers")
+	g.P("// sharing routes, of this package or others, have to be given the same map,")
+	g.P("// one per engine, without it each server keeps a map of its own")
+	g.P("func WithCORSPreflights(preflights *", syncPackage.Ident("Map"), ") HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.corsPreflights = preflights")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// registerCORSPreflight registers the preflight handler of a route on the")
+	g.P("// group, or adds it to the handler registered on the route by another")
+	g.P("// server, found in preflights by the base path of the group and the route")
+	g.P("func registerCORSPreflight(")
+	g.P("grp *", ginPackage.Ident("RouterGroup"), ",")
+	g.P("preflights *", syncPackage.Ident("Map"), ",")
+	g.P("route string,")
+	g.P("preflight ", ginPackage.Ident("HandlerFunc"), ",")
+	g.P(") {")
+	g.P("key := ", pathPackage.Ident("Join"), "(grp.BasePath(), route)")
+	g.P("mtx := ", syncPackage.Ident("RWMutex"), "{}")
+	g.P("chain := ", ginPackage.Ident("HandlersChain"), "{preflight}")
+	g.P("add := func(handler ", ginPackage.Ident("HandlerFunc"), ") {")
+	g.P("	mtx.Lock()")
+	g.P("	defer mtx.Unlock()")
+	g.P("	chain = append(chain, handler)")
+	g.P("}")
+	g.P("defer func() {")
+	g.P("	rec := recover()")
+	g.P("	if rec == nil {")
+	g.P("		return")
+	g.P("	}")
+	g.P("	// gin panics when the route of the engine already has a handler")
+	g.P("	msg := ", fmtPackage.Ident("Sprint"), "(rec)")
+	g.P(
+		"	if !",
+		stringsPackage.Ident("Contains"),
+		"(msg, \"handlers are already registered\") {",
+	)
+	g.P("		panic(rec)")
+	g.P("	}")
+	g.P("	existing, _ := preflights.Load(key)")
+	g.P("	addExisting, ok := existing.(func(", ginPackage.Ident("HandlerFunc"), "))")
+	g.P("	if !ok {")
+	g.P(
+		"		panic(msg + \", share the preflights of the servers registered on the route with WithCORSPreflights\")",
+	)
+	g.P("	}")
+	g.P("	addExisting(preflight)")
+	g.P("}()")
+	g.P("grp.OPTIONS(route, func(ctx *", ginPackage.Ident("Context"), ") {")
+	g.P("	mtx.RLock()")
+	g.P("	handlers := chain")
+	g.P("	mtx.RUnlock()")
+	g.P("	for _, handler := range handlers {")
+	g.P("		handler(ctx)")
+	g.P("	}")
+	g.P("	ctx.AbortWithStatus(204)")
+	g.P("})")
+	g.P("preflights.Store(key, add)")
+	g.P("}")
+	g.P("")
+}
+
+// renderServerCORSPolicy renders the cors policy variable of a service
+func renderServerCORSPolicy(
+	g *protogen.GeneratedFile,
+	srv Server,
+) {
+	headers := []string{}
+	anyHeader := false
+	for _, header := range srv.CORSAllowedHeaders() {
+		if header == "*" {
+			anyHeader = true
+		} else {
+			headers = append(headers, header)
+		}
+	}
+	maxAge := ""
+	if srv.CORS.MaxAgeSeconds != 0 {
+		maxAge = strconv.FormatUint(uint64(srv.CORS.MaxAgeSeconds), 10)
+	}
+	g.P("var ", srv.CORSPolicyName(), " = &httpCORSPolicy{")
+	g.P("origins: ", goStringSlice(srv.CORS.AllowedOrigins), ",")
+	g.P("headers: ", strconv.Quote(strings.Join(headers, ", ")), ",")
+	g.P("anyHeader: ", anyHeader, ",")
+	g.P("exposed: ", strconv.Quote(strings.Join(srv.CORSExposedHeaders(), ", ")), ",")
+	g.P("credentials: ", srv.CORS.AllowCredentials, ",")
+	g.P("maxAge: ", strconv.Quote(maxAge), ",")
+	g.P("}")
+	g.P("")
+}
+
+func renderRouteInfo(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+) {
+	g.P("var ", rpc.RouteInfoName(), " = &HTTPRouteInfo{")
+	g.P("Service: \"", rpc.Method.Parent.Desc.FullName(), "\",")
+	g.P("FullMethod: \"", rpc.FullMethod(), "\",")
+	g.P("HTTPMethod: \"", rpc.HTTPMethod, "\",")
+	g.P("Path: \"", rpc.OpenAPIPath, "\",")
+	g.P("Roles: ", goIdentSlice("PermissionRole", rpc.Roles), ",")
+	g.P("Features: ", goIdentSlice("PermissionFeature", rpc.Features), ",")
+	g.P("Tags: ", goStringSlice(rpc.Tags), ",")
+	g.P("Anonymous: ", rpc.Anonymous, ",")
+	if rpc.RateLimit != nil {
+		g.P("RateLimit: &RateLimit{")
+		g.P("Requests: ", rpc.RateLimit.Requests, ",")
+		g.P("Window: ", rpc.RateLimit.WindowSeconds, " * ", timePackage.Ident("Second"), ",")
+		g.P("Burst: ", rpc.RateLimit.Burst, ",")
+		g.P("},")
+	}
+	if rpc.Timeout != nil && rpc.Timeout.Seconds != 0 {
+		g.P("Timeout: ", rpc.Timeout.Seconds, " * ", timePackage.Ident("Second"), ",")
+	}
+	g.P("}")
+	g.P("")
+}
+
+func goStringSlice(vals []string) string {
+	quoted := make([]string, len(vals))
+	for idx := range vals {
+		quoted[idx] = strconv.Quote(vals[idx])
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+func goIdentSlice(typ string, vals []string) string {
+	idents := make([]string, len(vals))
+	for idx := range vals {
+		idents[idx] = permissionIdent(typ, vals[idx])
+	}
+	return "[]" + typ + "{" + strings.Join(idents, ", ") + "}"
+}
+
+// permissionIdent go identifier of a role or feature constant, ex:
+// orders.admin -> PermissionRoleOrdersAdmin
+func permissionIdent(typ string, name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	ident := typ
+	for _, part := range parts {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		ident += string(runes)
+	}
+	return ident
+}
+
+func renderResourceChecks(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+) {
+	action := "ResourceActionRead"
+	if rpc.IsCommand() {
+		action = "ResourceActionWrite"
+	}
+	for _, prm := range ResourceParameters(rpc.Parameters) {
+		id := "body." + prm.Getter()
+		switch prm.Type {
+		case Int32Type, Int64Type:
+			id = fmt.Sprintf(
+				"%s(int64(%s), 10)",
+				g.QualifiedGoIdent(strconvPackage.Ident("FormatInt")),
+				id,
+			)
+		case UInt32Type, UInt64Type:
+			id = fmt.Sprintf(
+				"%s(uint64(%s), 10)",
+				g.QualifiedGoIdent(strconvPackage.Ident("FormatUint")),
+				id,
+			)
+		}
+		g.P("authorized, err = p.opts.authorizer.CheckResource(")
+		g.P("c,")
+		g.P(strconv.Quote(prm.ResourceType), ",")
+		g.P(id, ",")
+		g.P(action, ",")
+		g.P(")")
+		g.P("if err != nil {")
+		g.P("	ctx.Error(err)")
+		g.P("	return")
+		g.P("}")
+		g.P("if !authorized {")
+		g.P("	ctx.Error(newForbiddenError())")
+		g.P("	return")
+		g.P("}")
+	}
+}
+
+func renderRateLimiter(g *protogen.GeneratedFile) {
+	g.P("// WithRateLimiter sets the limiter rate limited rpcs are counted against,")
+	g.P("// defaults to an in memory limiter local to the process")
+	g.P("func WithRateLimiter(limiter RateLimiter) HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.rateLimiter = limiter")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// WithRateLimitUserKey sets the function resolving the user of a request for")
+	g.P("// rpcs rate limited per user, requests without a user fall back to the")
+	g.P("// client ip")
+	g.P(
+		"func WithRateLimitUserKey(key func(ctx ",
+		contextPackage.Ident("Context"),
+		") string) HTTPServerOption {",
+	)
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.rateLimitUserKey = key")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// WithRateLimitClientIP groups calls limited by ip by gin's ClientIP, which")
+	g.P("// reads X-Forwarded-For and X-Real-IP when sent by one of the proxies trusted")
+	g.P("// with SetTrustedProxies. Calls are grouped by the address of the connection")
+	g.P("// by default, since gin trusts every proxy unless told otherwise and the")
+	g.P("// headers would let clients pick their own key")
+	g.P("func WithRateLimitClientIP() HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.rateLimitClientIP = true")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// rateLimitIP ip the calls of a client are grouped by")
+	g.P("func (o *httpServerOptions) rateLimitIP(ctx *", ginPackage.Ident("Context"), ") string {")
+	g.P("if o.rateLimitClientIP {")
+	g.P("	return ctx.ClientIP()")
+	g.P("}")
+	g.P("return ctx.RemoteIP()")
+	g.P("}")
+	g.P("")
+	g.P("// RateLimit limit applied to calls of an rpc, Requests calls are allowed")
+	g.P("// every Window with up to Burst calls at once")
+	g.P("type RateLimit struct {")
+	g.P("Requests uint32")
+	g.P("Window   ", timePackage.Ident("Duration"))
+	g.P("Burst    uint32")
+	g.P("}")
+	g.P("")
+	g.P("// RateLimitResult outcome of counting a call against a rate limit")
+	g.P("type RateLimitResult struct {")
+	g.P("Allowed   bool")
+	g.P("Remaining uint32")
+	g.P("// Reset time until the limit is fully replenished")
+	g.P("Reset ", timePackage.Ident("Duration"))
+	g.P("// RetryAfter time until the next call is allowed, set when not allowed")
+	g.P("RetryAfter ", timePackage.Ident("Duration"))
+	g.P("}")
+	g.P("")
+	g.P("// RateLimiter counts calls against the rate limit of an rpc, calls that are")
+	g.P("// not allowed respond with a 429 error")
+	g.P("type RateLimiter interface {")
+	g.P("Allow(")
+	g.P("ctx ", contextPackage.Ident("Context"), ",")
+	g.P("key string,")
+	g.P("limit RateLimit,")
+	g.P(") (RateLimitResult, error)")
+	g.P("}")
+	g.P("")
+	g.P("// NewMemoryRateLimiter creates a token bucket rate limiter holding its")
+	g.P("// buckets in memory, limits are not shared between processes")
+	g.P("func NewMemoryRateLimiter() RateLimiter {")
+	g.P("return &memoryRateLimiter{buckets: map[string]*rateLimitBucket{}}")
+	g.P("}")
+	g.P("")
+	g.P("type rateLimitBucket struct {")
+	g.P("tokens float64")
+	g.P("last ", timePackage.Ident("Time"))
+	g.P("full ", timePackage.Ident("Time"))
+	g.P("}")
+	g.P("")
+	g.P("type memoryRateLimiter struct {")
+	g.P("mtx ", syncPackage.Ident("Mutex"))
+	g.P("buckets map[string]*rateLimitBucket")
+	g.P("swept ", timePackage.Ident("Time"))
+	g.P("}")
+	g.P("")
+	g.P("func (l *memoryRateLimiter) Allow(")
+	g.P("_ ", contextPackage.Ident("Context"), ",")
+	g.P("key string,")
+	g.P("limit RateLimit,")
+	g.P(") (RateLimitResult, error) {")
+	g.P("burst := float64(limit.Burst)")
+	g.P("if burst == 0 {")
+	g.P("	burst = float64(limit.Requests)")
+	g.P("}")
+	g.P("rate := float64(limit.Requests) / limit.Window.Seconds()")
+	g.P("now := ", timePackage.Ident("Now"), "()")
+	g.P("")
+	g.P("l.mtx.Lock()")
+	g.P("defer l.mtx.Unlock()")
+	g.P("// buckets that have refilled are the same as new ones, dropping them keeps")
+	g.P("// the map from growing with every key seen")
+	g.P("if now.Sub(l.swept) > ", timePackage.Ident("Minute"), " {")
+	g.P("	for k, b := range l.buckets {")
+	g.P("		if now.After(b.full) {")
+	g.P("			delete(l.buckets, k)")
+	g.P("		}")
+	g.P("	}")
+	g.P("	l.swept = now")
+	g.P("}")
+	g.P("b, ok := l.buckets[key]")
+	g.P("if !ok {")
+	g.P("	b = &rateLimitBucket{tokens: burst, last: now}")
+	g.P("	l.buckets[key] = b")
+	g.P("}")
+	g.P("b.tokens = ", mathPackage.Ident("Min"), "(burst, b.tokens+now.Sub(b.last).Seconds()*rate)")
+	g.P("b.last = now")
+	g.P("")
+	g.P("res := RateLimitResult{}")
+	g.P("if b.tokens >= 1 {")
+	g.P("	b.tokens--")
+	g.P("	res.Allowed = true")
+	g.P("} else {")
+	g.P(
+		"	res.RetryAfter = ",
+		timePackage.Ident("Duration"),
+		"((1 - b.tokens) / rate * float64(",
+		timePackage.Ident("Second"),
+		"))",
+	)
+	g.P("}")
+	g.P("res.Remaining = uint32(b.tokens)")
+	g.P(
+		"res.Reset = ",
+		timePackage.Ident("Duration"),
+		"((burst - b.tokens) / rate * float64(",
+		timePackage.Ident("Second"),
+		"))",
+	)
+	g.P("b.full = now.Add(res.Reset)")
+	g.P("return res, nil")
+	g.P("}")
+	g.P("")
+	g.P("// writeRateLimitHeaders writes the RateLimit headers of a rate limited call")
+	g.P("func writeRateLimitHeaders(")
+	g.P("ctx *", ginPackage.Ident("Context"), ",")
+	g.P("limit *RateLimit,")
+	g.P("res RateLimitResult,")
+	g.P(") {")
+	g.P("seconds := func(d ", timePackage.Ident("Duration"), ") string {")
+	g.P(
+		"	return ",
+		strconvPackage.Ident("FormatInt"),
+		"(int64(",
+		mathPackage.Ident("Ceil"),
+		"(d.Seconds())), 10)",
+	)
+	g.P("}")
+	g.P(
+		"ctx.Header(\"RateLimit-Limit\", ",
+		strconvPackage.Ident("FormatUint"),
+		"(uint64(limit.Requests), 10))",
+	)
+	g.P(
+		"ctx.Header(\"RateLimit-Remaining\", ",
+		strconvPackage.Ident("FormatUint"),
+		"(uint64(res.Remaining), 10))",
+	)
+	g.P("ctx.Header(\"RateLimit-Reset\", seconds(res.Reset))")
+	g.P("if !res.Allowed {")
+	g.P("	ctx.Header(\"Retry-After\", seconds(res.RetryAfter))")
+	g.P("}")
+	g.P("}")
+	g.P("")
+}
+
+func renderRateLimitCheck(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+) {
+	g.P("{")
+	switch rpc.RateLimit.Key {
+	case "user":
+		g.P("key := \"ip:\" + p.opts.rateLimitIP(ctx)")
+		g.P("if p.opts.rateLimitUserKey != nil {")
+		g.P("	if user := p.opts.rateLimitUserKey(c); user != \"\" {")
+		g.P("		key = \"user:\" + user")
+		g.P("	}")
+		g.P("}")
+	case "header":
+		g.P("key := \"ip:\" + p.opts.rateLimitIP(ctx)")
+		g.P("if val := ctx.GetHeader(", strconv.Quote(rpc.RateLimit.Header), "); val != \"\" {")
+		g.P("	key = \"header:\" + val")
+		g.P("}")
+	default:
+		g.P("key := \"ip:\" + p.opts.rateLimitIP(ctx)")
+	}
+	g.P("res, err := p.opts.rateLimiter.Allow(")
+	g.P("c,")
+	g.P("\"", rpc.FullMethod(), ":\"+key,")
+	g.P("*", rpc.RouteInfoName(), ".RateLimit,")
+	g.P(")")
+	g.P("if err != nil {")
+	g.P("	ctx.Error(err)")
+	g.P("	return")
+	g.P("}")
+	g.P("writeRateLimitHeaders(ctx, ", rpc.RouteInfoName(), ".RateLimit, res)")
+	g.P("if !res.Allowed {")
+	g.P("	ctx.Error(newTooManyRequestsError())")
+	g.P("	return")
+	g.P("}")
+	g.P("}")
+}
+
+func renderIdempotencyStore(g *protogen.GeneratedFile) {
+	g.P("// WithIdempotencyStore sets the store responses of idempotent rpcs are kept")
+	g.P("// in, defaults to an in memory store keeping responses for a day")
+	g.P("func WithIdempotencyStore(store IdempotencyStore) HTTPServerOption {")
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.idempotencyStore = store")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// WithIdempotencyCallerKey sets the function resolving the caller idempotency")
+	g.P("// keys are scoped to, defaults to a hash of the Authorization and Cookie")
+	g.P("// headers of the request")
+	g.P(
+		"func WithIdempotencyCallerKey(key func(ctx ",
+		contextPackage.Ident("Context"),
+		") string) HTTPServerOption {",
+	)
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.idempotencyCallerKey = key")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// IdempotencyResponse response of a completed call, replayed to the calls")
+	g.P("// repeating its key")
+	g.P("type IdempotencyResponse struct {")
+	g.P("Status int")
+	g.P("// Header headers describing the response, ex. Content-Type or ETag, the")
+	g.P("// ones describing the call, ex. the RateLimit or CORS headers, are not")
+	g.P("// replayed")
+	g.P("Header ", nethttpPackage.Ident("Header"))
+	g.P("Body   []byte")
+	g.P("}")
+	g.P("")
+	g.P("// IdempotencyRecord call made with an idempotency key")
+	g.P("type IdempotencyRecord struct {")
+	g.P("// Fingerprint hash of the request the key was first used with")
+	g.P("Fingerprint string")
+	g.P("Completed   bool")
+	g.P("Response    IdempotencyResponse")
+	g.P("}")
+	g.P("")
+	g.P("// IdempotencyStore keeps track of calls made to idempotent rpcs by their")
+	g.P("// Idempotency-Key header, keys are prefixed with the full method of the rpc")
+	g.P("// and the caller")
+	g.P("type IdempotencyStore interface {")
+	g.P("// Begin records the start of a call with the key, returning the existing")
+	g.P("// record when the key has been used before")
+	g.P("Begin(")
+	g.P("ctx ", contextPackage.Ident("Context"), ",")
+	g.P("key string,")
+	g.P("fingerprint string,")
+	g.P(") (*IdempotencyRecord, error)")
+	g.P("// Complete stores the response of a call that succeeded")
+	g.P("Complete(")
+	g.P("ctx ", contextPackage.Ident("Context"), ",")
+	g.P("key string,")
+	g.P("response IdempotencyResponse,")
+	g.P(") error")
+	g.P("// Abort releases the key of a call that failed so it can be retried")
+	g.P("Abort(ctx ", contextPackage.Ident("Context"), ", key string) error")
+	g.P("}")
+	g.P("")
+	g.P("// NewMemoryIdempotencyStore creates an idempotency store holding records in")
+	g.P("// memory for the ttl, records are not shared between processes")
+	g.P(
+		"func NewMemoryIdempotencyStore(ttl ",
+		timePackage.Ident("Duration"),
+		") IdempotencyStore {",
+	)
+	g.P("return &memoryIdempotencyStore{")
+	g.P("	ttl:     ttl,")
+	g.P("	records: map[string]*memoryIdempotencyRecord{},")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("type memoryIdempotencyRecord struct {")
+	g.P("IdempotencyRecord")
+	g.P("expires ", timePackage.Ident("Time"))
+	g.P("}")
+	g.P("")
+	g.P("type memoryIdempotencyStore struct {")
+	g.P("mtx ", syncPackage.Ident("Mutex"))
+	g.P("ttl ", timePackage.Ident("Duration"))
+	g.P("records map[string]*memoryIdempotencyRecord")
+	g.P("swept ", timePackage.Ident("Time"))
+	g.P("}")
+	g.P("")
+	g.P("func (s *memoryIdempotencyStore) Begin(")
+	g.P("_ ", contextPackage.Ident("Context"), ",")
+	g.P("key string,")
+	g.P("fingerprint string,")
+	g.P(") (*IdempotencyRecord, error) {")
+	g.P("now := ", timePackage.Ident("Now"), "()")
+	g.P("s.mtx.Lock()")
+	g.P("defer s.mtx.Unlock()")
+	g.P("if now.Sub(s.swept) > ", timePackage.Ident("Minute"), " {")
+	g.P("	for k, rec := range s.records {")
+	g.P("		if now.After(rec.expires) {")
+	g.P("			delete(s.records, k)")
+	g.P("		}")
+	g.P("	}")
+	g.P("	s.swept = now")
+	g.P("}")
+	g.P("if rec, ok := s.records[key]; ok && now.Before(rec.expires) {")
+	g.P("	found := rec.IdempotencyRecord")
+	g.P("	return &found, nil")
+	g.P("}")
+	g.P("s.records[key] = &memoryIdempotencyRecord{")
+	g.P("	IdempotencyRecord: IdempotencyRecord{Fingerprint: fingerprint},")
+	g.P("	expires:           now.Add(s.ttl),")
+	g.P("}")
+	g.P("return nil, nil")
+	g.P("}")
+	g.P("")
+	g.P("func (s *memoryIdempotencyStore) Complete(")
+	g.P("_ ", contextPackage.Ident("Context"), ",")
+	g.P("key string,")
+	g.P("response IdempotencyResponse,")
+	g.P(") error {")
+	g.P("s.mtx.Lock()")
+	g.P("defer s.mtx.Unlock()")
+	g.P("if rec, ok := s.records[key]; ok {")
+	g.P("	rec.Completed = true")
+	g.P("	rec.Response = response")
+	g.P("	rec.expires = ", timePackage.Ident("Now"), "().Add(s.ttl)")
+	g.P("}")
+	g.P("return nil")
+	g.P("}")
+	g.P("")
+	g.P(
+		"func (s *memoryIdempotencyStore) Abort(_ ",
+		contextPackage.Ident("Context"),
+		", key string) error {",
+	)
+	g.P("s.mtx.Lock()")
+	g.P("defer s.mtx.Unlock()")
+	g.P("delete(s.records, key)")
+	g.P("return nil")
+	g.P("}")
+	g.P("")
+	g.P("// idempotencyCaller hashes the credentials of a request, so that idempotency")
+	g.P("// keys of different callers do not collide")
+	g.P("func idempotencyCaller(ctx *", ginPackage.Ident("Context"), ") string {")
+	g.P("h := ", sha256Package.Ident("New"), "()")
+	g.P("h.Write([]byte(ctx.GetHeader(\"Authorization\") + \"\\n\"))")
+	g.P("h.Write([]byte(ctx.GetHeader(\"Cookie\")))")
+	g.P("return ", hexPackage.Ident("EncodeToString"), "(h.Sum(nil))")
+	g.P("}")
+	g.P("")
+	g.P("// idempotencyHeaders headers of a response replayed to the calls repeating")
+	g.P("// its idempotency key")
+	g.P("var idempotencyHeaders = []string{")
+	g.P("\"Content-Type\",")
+	g.P("\"Content-Disposition\",")
+	g.P("\"Content-Language\",")
+	g.P("\"ETag\",")
+	g.P("\"Last-Modified\",")
+	g.P("\"Location\",")
+	g.P("}")
+	g.P("")
+	g.P("// newIdempotencyResponse response stored for the idempotency key of a call")
+	g.P(
+		"func newIdempotencyResponse(ctx *",
+		ginPackage.Ident("Context"),
+		", body []byte) IdempotencyResponse {",
+	)
+	g.P("header := ", nethttpPackage.Ident("Header"), "{}")
+	g.P("for _, key := range idempotencyHeaders {")
+	g.P("	for _, val := range ctx.Writer.Header().Values(key) {")
+	g.P("		header.Add(key, val)")
+	g.P("	}")
+	g.P("}")
+	g.P("return IdempotencyResponse{")
+	g.P("	Status: ctx.Writer.Status(),")
+	g.P("	Header: header,")
+	g.P("	Body:   body,")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// replayIdempotencyResponse writes the stored response of a call repeating")
+	g.P("// an idempotency key")
+	g.P(
+		"func replayIdempotencyResponse(ctx *",
+		ginPackage.Ident("Context"),
+		", res IdempotencyResponse) {",
+	)
+	g.P("for key, vals := range res.Header {")
+	g.P("	ctx.Writer.Header().Del(key)")
+	g.P("	for _, val := range vals {")
+	g.P("		ctx.Writer.Header().Add(key, val)")
+	g.P("	}")
+	g.P("}")
+	g.P("ctx.Header(\"Idempotent-Replayed\", \"true\")")
+	g.P("if res.Status == 0 {")
+	g.P("	res.Status = 200")
+	g.P("}")
+	g.P("ctx.Status(res.Status)")
+	g.P("if _, err := ctx.Writer.Write(res.Body); err != nil {")
+	g.P("	ctx.Error(err)")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// idempotencyFingerprint hashes the parts of a request that a call repeating")
+	g.P("// an idempotency key has to match")
+	g.P("func idempotencyFingerprint(ctx *", ginPackage.Ident("Context"), ", raw []byte) string {")
+	g.P("h := ", sha256Package.Ident("New"), "()")
+	g.P("h.Write([]byte(ctx.Request.Method + \" \" + ctx.Request.URL.RequestURI() + \"\\n\"))")
+	g.P("h.Write(raw)")
+	g.P("return ", hexPackage.Ident("EncodeToString"), "(h.Sum(nil))")
+	g.P("}")
+	g.P("")
+}
+
+func renderTelemetry(g *protogen.GeneratedFile) {
+	g.P("// WithTracerProvider sets the provider of the tracer spans of rpcs are")
+	g.P("// started with, defaults to the global provider")
+	g.P(
+		"func WithTracerProvider(provider ",
+		oteltracePackage.Ident("TracerProvider"),
+		") HTTPServerOption {",
+	)
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.tracerProvider = provider")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// WithMeterProvider sets the provider of the meter rpc metrics are recorded")
+	g.P("// with, defaults to the global provider")
+	g.P(
+		"func WithMeterProvider(provider ",
+		otelmetPackage.Ident("MeterProvider"),
+		") HTTPServerOption {",
+	)
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.meterProvider = provider")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P("// WithPropagator sets the propagator extracting the parent span from the")
+	g.P("// request headers, defaults to the global propagator")
+	g.P(
+		"func WithPropagator(propagator ",
+		otelpropPackage.Ident("TextMapPropagator"),
+		") HTTPServerOption {",
+	)
+	g.P("return func(o *httpServerOptions) {")
+	g.P("	o.propagator = propagator")
+	g.P("}")
+	g.P("}")
+	g.P("")
+	g.P(
+		"const httpInstrumentationName = \"github.com/BetaLixT/golang-tooling/protoc-gen-goblthttp\"",
+	)
+	g.P("")
+	g.P("// httpTelemetry spans and metrics of the rpcs of a server")
+	g.P("type httpTelemetry struct {")
+	g.P("tracer       ", oteltracePackage.Ident("Tracer"))
+	g.P("propagator   ", otelpropPackage.Ident("TextMapPropagator"))
+	g.P("duration     ", otelmetPackage.Ident("Float64Histogram"))
+	g.P("requestSize  ", otelmetPackage.Ident("Int64Histogram"))
+	g.P("responseSize ", otelmetPackage.Ident("Int64Histogram"))
+	g.P("}")
+	g.P("")
+	g.P("func newHTTPTelemetry(opts httpServerOptions) *httpTelemetry {")
+	g.P("tp, mp, prop := opts.tracerProvider, opts.meterProvider, opts.propagator")
+	g.P("if tp == nil {")
+	g.P("	tp = ", otelPackage.Ident("GetTracerProvider"), "()")
+	g.P("}")
+	g.P("if mp == nil {")
+	g.P("	mp = ", otelPackage.Ident("GetMeterProvider"), "()")
+	g.P("}")
+	g.P("if prop == nil {")
+	g.P("	prop = ", otelPackage.Ident("GetTextMapPropagator"), "()")
+	g.P("}")
+	g.P("meter := mp.Meter(httpInstrumentationName)")
+	g.P("noop := ", otelnoopPackage.Ident("Meter"), "{}")
+	g.P("t := &httpTelemetry{")
+	g.P("tracer:     tp.Tracer(httpInstrumentationName),")
+	g.P("propagator: prop,")
+	g.P("}")
+	g.P("var err error")
+	g.P("if t.duration, err = meter.Float64Histogram(")
+	g.P("\"http.server.request.duration\",")
+	g.P(otelmetPackage.Ident("WithUnit"), "(\"s\"),")
+	g.P(otelmetPackage.Ident("WithDescription"), "(\"Duration of HTTP server requests.\"),")
+	g.P("); err != nil {")
+	g.P("	", otelPackage.Ident("Handle"), "(err)")
+	g.P("	t.duration, _ = noop.Float64Histogram(\"\")")
+	g.P("}")
+	g.P("if t.requestSize, err = meter.Int64Histogram(")
+	g.P("\"http.server.request.body.size\",")
+	g.P(otelmetPackage.Ident("WithUnit"), "(\"By\"),")
+	g.P(otelmetPackage.Ident("WithDescription"), "(\"Size of HTTP server request bodies.\"),")
+	g.P("); err != nil {")
+	g.P("	", otelPackage.Ident("Handle"), "(err)")
+	g.P("	t.requestSize, _ = noop.Int64Histogram(\"\")")
+	g.P("}")
+	g.P("if t.responseSize, err = meter.Int64Histogram(")
+	g.P("\"http.server.response.body.size\",")
+	g.P(otelmetPackage.Ident("WithUnit"), "(\"By\"),")
+	g.P(otelmetPackage.Ident("WithDescription"), "(\"Size of HTTP server response bodies.\"),")
+	g.P("); err != nil {")
+	g.P("	", otelPackage.Ident("Handle"), "(err)")
+	g.P("	t.responseSize, _ = noop.Int64Histogram(\"\")")
+	g.P("}")
+	g.P("return t")
+	g.P("}")
+	g.P("")
+	g.P("// start starts the span of an rpc as a child of the span propagated in the")
+	g.P("// request headers, the request context is replaced with one holding it")
+	g.P("func (t *httpTelemetry) start(")
+	g.P("ctx *", ginPackage.Ident("Context"), ",")
+	g.P("info *HTTPRouteInfo,")
+	g.P(") ", oteltracePackage.Ident("Span"), " {")
+	g.P("parent := t.propagator.Extract(")
+	g.P("ctx.Request.Context(),")
+	g.P(otelpropPackage.Ident("HeaderCarrier"), "(ctx.Request.Header),")
+	g.P(")")
+	g.P("spanCtx, span := t.tracer.Start(")
+	g.P("parent,")
+	g.P(stringsPackage.Ident("TrimPrefix"), "(info.FullMethod, \"/\"),")
+	g.P(oteltracePackage.Ident("WithSpanKind"), "(", oteltracePackage.Ident("SpanKindServer"), "),")
+	g.P(oteltracePackage.Ident("WithAttributes"), "(")
+	g.P(otelattrPackage.Ident("String"), "(\"http.request.method\", info.HTTPMethod),")
+	g.P(otelattrPackage.Ident("String"), "(\"http.route\", info.Path),")
+	g.P(otelattrPackage.Ident("String"), "(\"rpc.service\", info.Service),")
+	g.P(
+		otelattrPackage.Ident("String"),
+		"(\"rpc.method\", ",
+		pathPackage.Ident("Base"),
+		"(info.FullMethod)),",
+	)
+	g.P("),")
+	g.P(")")
+	g.P("ctx.Request = ctx.Request.WithContext(spanCtx)")
+	g.P("return span")
+	g.P("}")
+	g.P("")
+	g.P("// end records the errors of the call on its span, ends it and records the")
+	g.P("// metrics of the call, errors not yet written are assumed to be written with")
+	g.P("// their status code by a later handler")
+	g.P("func (t *httpTelemetry) end(")
+	g.P("ctx *", ginPackage.Ident("Context"), ",")
+	g.P("info *HTTPRouteInfo,")
+	g.P("span ", oteltracePackage.Ident("Span"), ",")
+	g.P("start ", timePackage.Ident("Time"), ",")
+	g.P(") {")
+	g.P("status := ctx.Writer.Status()")
+	g.P("if len(ctx.Errors) != 0 {")
+	g.P("	if !ctx.Writer.Written() {")
+	g.P("		status = 500")
+	g.P("		var gerr *", gorrPackage.Ident("Error"))
+	g.P("		if ", errorsPackage.Ident("As"), "(ctx.Errors.Last().Err, &gerr) {")
+	g.P("			status = gerr.StatusCode")
+	g.P("		}")
+	g.P("	}")
+	g.P("	for _, e := range ctx.Errors {")
+	g.P("		span.RecordError(e.Err)")
+	g.P("	}")
+	g.P("}")
+	g.P("if status >= 500 {")
+	g.P("	span.SetStatus(", otelcodesPackage.Ident("Error"), ", ", "ctx.Errors.String())")
+	g.P("}")
+	g.P(
+		"span.SetAttributes(",
+		otelattrPackage.Ident("Int"),
+		"(\"http.response.status_code\", status))",
+	)
+	g.P("span.End()")
+	g.P("")
+	g.P("attrs := ", otelmetPackage.Ident("WithAttributes"), "(")
+	g.P(otelattrPackage.Ident("String"), "(\"http.request.method\", info.HTTPMethod),")
+	g.P(otelattrPackage.Ident("String"), "(\"http.route\", info.Path),")
+	g.P(otelattrPackage.Ident("String"), "(\"rpc.service\", info.Service),")
+	g.P(
+		otelattrPackage.Ident("String"),
+		"(\"rpc.method\", ",
+		pathPackage.Ident("Base"),
+		"(info.FullMethod)),",
+	)
+	g.P(otelattrPackage.Ident("Int"), "(\"http.response.status_code\", status),")
+	g.P(")")
+	g.P("rctx := ctx.Request.Context()")
+	g.P("t.duration.Record(rctx, ", timePackage.Ident("Since"), "(start).Seconds(), attrs)")
+	g.P("if ctx.Request.ContentLength >= 0 {")
+	g.P("	t.requestSize.Record(rctx, ctx.Request.ContentLength, attrs)")
+	g.P("}")
+	g.P("if size := ctx.Writer.Size(); size >= 0 {")
+	g.P("	t.responseSize.Record(rctx, int64(size), attrs)")
+	g.P("}")
+	g.P("}")
+	g.P("")
+}
+
+func renderIdempotencyBegin(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+) {
+	raw := "raw"
+	if rpc.HTTPMethod == "GET" || rpc.HTTPMethod == "DELETE" {
+		raw = "nil"
+	}
+	g.P("idempotencyKey := ctx.GetHeader(\"Idempotency-Key\")")
+	g.P("idempotencyCompleted := false")
+	g.P("if idempotencyKey != \"\" {")
+	g.P("caller := idempotencyCaller(ctx)")
+	g.P("if p.opts.idempotencyCallerKey != nil {")
+	g.P("	caller = p.opts.idempotencyCallerKey(c)")
+	g.P("}")
+	g.P("idempotencyKey = \"", rpc.FullMethod(), ":\" + caller + \":\" + idempotencyKey")
+	g.P("fingerprint := idempotencyFingerprint(ctx, ", raw, ")")
+	g.P("rec, err := p.opts.idempotencyStore.Begin(c, idempotencyKey, fingerprint)")
+	g.P("if err != nil {")
+	g.P("	ctx.Error(err)")
+	g.P("	return")
+	g.P("}")
+	g.P("if rec != nil {")
+	g.P("if rec.Fingerprint != fingerprint {")
+	g.P("	ctx.Error(newIdempotencyConflictError(")
+	g.P("		\"idempotency key was used with a different request\",")
+	g.P("	))")
+	g.P("	return")
+	g.P("}")
+	g.P("if !rec.Completed {")
+	g.P("	ctx.Error(newIdempotencyConflictError(")
+	g.P("		\"a request with the idempotency key is in progress\",")
+	g.P("	))")
+	g.P("	return")
+	g.P("}")
+	g.P("replayIdempotencyResponse(ctx, rec.Response)")
+	g.P("return")
+	g.P("}")
+	g.P("// releases the key of calls that failed or panicked so they can be retried")
+	g.P("defer func() {")
+	g.P("if idempotencyCompleted {")
+	g.P("	return")
+	g.P("}")
+	g.P("if err := p.opts.idempotencyStore.Abort(c, idempotencyKey); err != nil {")
+	g.P("	ctx.Error(err)")
+	g.P("}")
+	g.P("}()")
+	g.P("}")
+}
+
+// renderRawBody binds a raw or multipart request body, or leaves it to the rpc
+// to read when streamed, the other parameters are bound from the query or
+// form values
+func renderRawBody(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+	opts Options,
+) {
+	g.P(
+		"ctx.Request.Body = ",
+		nethttpPackage.Ident("MaxBytesReader"),
+		"(ctx.Writer, ctx.Request.Body, ",
+		rpc.Body.MaxBytes,
+		")",
+	)
+	if len(rpc.Body.ContentTypes) != 0 && !rpc.Body.Multipart {
+		g.P("if !contentTypeAllowed(ctx, ", goStringSlice(rpc.Body.ContentTypes), ") {")
+		g.P("	ctx.Error(newUnsupportedMediaTypeError())")
+		g.P("	return")
+		g.P("}")
+	}
+	switch {
+	case rpc.Body.Multipart && rpc.Body.Stream:
+		for _, prm := range rpc.Parameters {
+			if prm.In != "form" {
+				continue
+			}
+			form := "_"
+			if bindsQueryParameters(rpc.Parameters) {
+				form = "form"
+			}
+			g.P(form, ", file, err := readFormValues(ctx, \"", prm.RequestedKey, "\")")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(err)")
+			g.P("	return")
+			g.P("}")
+			g.P("if file != nil {")
+			renderStreamedField(g, rpc, "file")
+			if !prm.IsOptional {
+				g.P("} else {")
+				g.P("ctx.Error(newMissingRequiredParametersError(\"", prm.RequestedKey, "\"))")
+				g.P("return")
+			}
+			g.P("}")
+		}
+		renderQueryParameters(g, rpc.Parameters, []string{}, "form", "Form", opts)
+	case rpc.Body.Multipart:
+		g.P("if err := ctx.Request.ParseMultipartForm(32 << 20); err != nil {")
+		g.P(
+			"if ",
+			errorsPackage.Ident("Is"),
+			"(err, ",
+			nethttpPackage.Ident("ErrNotMultipart"),
+			") {",
+		)
+		g.P("	ctx.Error(newUnsupportedMediaTypeError())")
+		g.P("	return")
+		g.P("}")
+		g.P("ctx.Error(bodyReadError(err))")
+		g.P("return")
+		g.P("}")
+		renderQueryParameters(g, rpc.Parameters, []string{}, "ctx", "PostForm", opts)
+		for _, prm := range rpc.Parameters {
+			if prm.In != "form" {
+				continue
+			}
+			g.P("if data, ok, err := readFormFile(ctx, \"", prm.RequestedKey, "\"); err != nil {")
+			g.P("	ctx.Error(err)")
+			g.P("	return")
+			g.P("} else if ok {")
+			g.P("	body.", prm.FullParameter, " = data")
+			g.P("} else {")
+			if prm.IsOptional {
+				g.P("body.", prm.FullParameter, " = nil")
+			} else {
+				g.P("ctx.Error(newMissingRequiredParametersError(\"", prm.RequestedKey, "\"))")
+				g.P("return")
+			}
+			g.P("}")
+		}
+	case rpc.Body.Stream:
+		renderStreamedField(g, rpc, "ctx.Request.Body")
+		renderQueryParameters(g, rpc.Parameters, []string{}, "ctx", "Query", opts)
+	default:
+		g.P("raw, err := ", ioutilPackage.Ident("ReadAll"), "(ctx.Request.Body)")
+		g.P("if err != nil {")
+		g.P("	ctx.Error(bodyReadError(err))")
+		g.P("	return")
+		g.P("}")
+		g.P("body.", rpc.Body.Field.GoName, " = raw")
+		renderQueryParameters(g, rpc.Parameters, []string{}, "ctx", "Query", opts)
+	}
+}
+
+// renderStreamedField passes the reader of the streamed field to the rpc
+// through the context it is invoked with
+// bindsQueryParameters checks if any of the parameters is bound from the
+// query, or the form values of a multipart body
+func bindsQueryParameters(prms []Parameter) bool {
+	for _, prm := range prms {
+		if prm.IsPath || prm.In != "" || prm.ServerPopulated {
+			continue
+		}
+		if len(prm.Holding) == 0 || bindsQueryParameters(prm.Holding) {
+			return true
+		}
+	}
+	return false
+}
+
+func renderStreamedField(g *protogen.GeneratedFile, rpc APIPath, reader string) {
+	g.P(
+		"c = ",
+		contextPackage.Ident("WithValue"),
+		"(c, streamedFieldKey{\"",
+		rpc.Body.Field.Desc.FullName(),
+		"\"}, ",
+		ioPackage.Ident("Reader"),
+		"(",
+		reader,
+		"))",
+	)
+}
+
+// renderStreamedFieldReaders renders the readers of the streamed fields of the
+// inputs, falling back to the content of the field when the rpc was not
+// invoked through the http server
+func renderStreamedFieldReaders(g *protogen.GeneratedFile, srvs []Server) {
+	for _, srv := range srvs {
+		for _, rpc := range srv.Paths {
+			if rpc.Body == nil || !rpc.Body.Stream {
+				continue
+			}
+			field := rpc.Body.Field
+			g.P("")
+			g.P(
+				"// ",
+				field.GoName,
+				"Reader reads the ",
+				field.Desc.Name(),
+				" field, streamed from the request body when",
+			)
+			g.P("// the rpc is invoked through the http server")
+			g.P(
+				"func (x *",
+				rpc.Method.Input.GoIdent,
+				") ",
+				field.GoName,
+				"Reader(ctx ",
+				contextPackage.Ident("Context"),
+				") ",
+				ioPackage.Ident("Reader"),
+				" {",
+			)
+			g.P(
+				"if r, ok := ctx.Value(streamedFieldKey{\"",
+				field.Desc.FullName(),
+				"\"}).(",
+				ioPackage.Ident("Reader"),
+				"); ok {",
+			)
+			g.P("	return r")
+			g.P("}")
+			g.P("return ", bytesPackage.Ident("NewReader"), "(x.Get", field.GoName, "())")
+			g.P("}")
+		}
+	}
+}
+
+// renderRawResponseHeaders sets the content type and disposition of a raw
+// response
+func renderRawResponseHeaders(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+) {
+	g.P("contentType := ", strconv.Quote(rpc.RawResponse.ContentType))
+	if rpc.RawResponse.ContentTypeField != nil {
+		g.P("if val := res.Get", rpc.RawResponse.ContentTypeField.GoName, "(); val != \"\" {")
+		g.P("	contentType = val")
+		g.P("}")
+	}
+	g.P("ctx.Header(\"Content-Type\", contentType)")
+	disposition := "attachment"
+	if rpc.RawResponse.Inline {
+		disposition = "inline"
+	}
+	if rpc.RawResponse.FilenameField != nil {
+		g.P("if name := res.Get", rpc.RawResponse.FilenameField.GoName, "(); name != \"\" {")
+		g.P(
+			"ctx.Header(\"Content-Disposition\", ",
+			mimePackage.Ident("FormatMediaType"),
+			"(\"",
+			disposition,
+			"\", map[string]string{\"filename\": name}))",
+		)
+		g.P("} else {")
+		g.P("	ctx.Header(\"Content-Disposition\", \"", disposition, "\")")
+		g.P("}")
+	} else {
+		g.P("ctx.Header(\"Content-Disposition\", \"", disposition, "\")")
+	}
+}
+
+// renderTimeout applies the deadline of the rpc to the context it is invoked
+// with
+func renderTimeout(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+) {
+	g.P("{")
+	g.P("timeout := ", rpc.RouteInfoName(), ".Timeout")
+	if rpc.Timeout.FromHeaders {
+		g.P("if d, ok := requestTimeout(ctx); ok && d > 0 && (timeout == 0 || d < timeout) {")
+		g.P("	timeout = d")
+		g.P("}")
+	}
+	g.P("if timeout > 0 {")
+	g.P("var cancel ", contextPackage.Ident("CancelFunc"))
+	g.P("invokeCtx, cancel = ", contextPackage.Ident("WithTimeout"), "(invokeCtx, timeout)")
+	g.P("defer cancel()")
+	g.P("}")
+	g.P("}")
+}
+
+func renderCacheHeaders(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+) {
+	if rpc.Cache.ETagField == nil {
+		g.P("etag := responseETag(resraw)")
+	} else {
+		g.P("etag := versionETag(", formatField(g, rpc.Cache.ETagField, "res"), ")")
+	}
+	g.P("ctx.Header(\"ETag\", etag)")
+	g.P("ctx.Header(\"Cache-Control\", ", strconv.Quote(rpc.Cache.Control()), ")")
+	if len(rpc.Cache.Vary) != 0 {
+		g.P(
+			"ctx.Writer.Header().Add(\"Vary\", ",
+			strconv.Quote(strings.Join(rpc.Cache.Vary, ", ")),
+			")",
+		)
+	}
+	g.P("if etagMatches(ctx.GetHeader(\"If-None-Match\"), etag) {")
+	g.P("	ctx.Status(304)")
+	g.P("	ctx.Writer.WriteHeaderNow()")
+	g.P("	return")
+	g.P("}")
+}
+
+func renderPageSizeCheck(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+	opts Options,
+) {
+	size := "body." + rpc.Pagination.PageSize.GoName
+	g.P("if ", size, " < 0 {")
+	g.P(
+		"	ctx.Error(newUnparsableParameterError(\"",
+		opts.FieldName(rpc.Pagination.PageSize.Desc),
+		"\"))",
+	)
+	g.P("	return")
+	g.P("}")
+	g.P("if ", size, " > ", rpc.Pagination.MaxPageSize, " {")
+	g.P("	", size, " = ", rpc.Pagination.MaxPageSize)
+	g.P("}")
+}
+
+func renderIfMatch(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+) {
+	field := rpc.Concurrency.Field
+	g.P("if ifMatch := ctx.GetHeader(\"If-Match\"); ifMatch != \"\" && ifMatch != \"*\" {")
+	g.P("tags, ok := parseIfMatch(ifMatch)")
+	g.P("if !ok {")
+	g.P("	ctx.Error(newUnparsableParameterError(\"If-Match\"))")
+	g.P("	return")
+	g.P("}")
+	g.P("// the rpc compares a single version, weak tags can never match and lists of")
+	g.P("// versions are not supported")
+	g.P("if len(tags) != 1 {")
+	g.P("	ctx.Error(newPreconditionFailedError())")
+	g.P("	return")
+	g.P("}")
+	g.P("version := tags[0]")
+	val := "version"
+	switch field.Desc.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Int64Kind:
+		bits, typ := "64", "int64"
+		if field.Desc.Kind() == protoreflect.Int32Kind {
+			bits, typ = "32", "int32"
+		}
+		g.P("parsed, err := ", strconvPackage.Ident("ParseInt"), "(version, 10, ", bits, ")")
+		g.P("if err != nil {")
+		g.P("	ctx.Error(newPreconditionFailedError())")
+		g.P("	return")
+		g.P("}")
+		g.P("val := ", typ, "(parsed)")
+		val = "val"
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind:
+		bits, typ := "64", "uint64"
+		if field.Desc.Kind() == protoreflect.Uint32Kind {
+			bits, typ = "32", "uint32"
 		}
+		g.P("parsed, err := ", strconvPackage.Ident("ParseUint"), "(version, 10, ", bits, ")")
+		g.P("if err != nil {")
+		g.P("	ctx.Error(newPreconditionFailedError())")
+		g.P("	return")
 		g.P("}")
+		g.P("val := ", typ, "(parsed)")
+		val = "val"
+	}
+	if field.Desc.HasOptionalKeyword() {
+		val = "&" + val
 	}
+	g.P("body.", field.GoName, " = ", val)
+	if rpc.Concurrency.Required {
+		g.P("} else if ifMatch == \"\" {")
+		g.P("	ctx.Error(newPreconditionRequiredError())")
+		g.P("	return")
+	}
+	g.P("}")
+}
 
-	return nil
+// formatField expression formatting a string or integer field of a message
+// as a string
+func formatField(g *protogen.GeneratedFile, field *protogen.Field, msg string) string {
+	getter := msg + ".Get" + field.GoName + "()"
+	switch field.Desc.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Int64Kind:
+		return fmt.Sprintf(
+			"%s(int64(%s), 10)",
+			g.QualifiedGoIdent(strconvPackage.Ident("FormatInt")),
+			getter,
+		)
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind:
+		return fmt.Sprintf(
+			"%s(uint64(%s), 10)",
+			g.QualifiedGoIdent(strconvPackage.Ident("FormatUint")),
+			getter,
+		)
+	}
+	return getter
+}
+
+func renderDeprecationHeaders(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+) {
+	if rpc.Deprecation.Since.IsZero() {
+		g.P("ctx.Header(\"Deprecation\", \"true\")")
+	} else {
+		g.P(
+			"ctx.Header(\"Deprecation\", \"@",
+			strconv.FormatInt(rpc.Deprecation.Since.Unix(), 10),
+			"\")",
+		)
+	}
+	if !rpc.Deprecation.Sunset.IsZero() {
+		g.P(
+			"ctx.Header(\"Sunset\", \"",
+			rpc.Deprecation.Sunset.UTC().Format(http.TimeFormat),
+			"\")",
+		)
+	}
+	if rpc.Deprecation.Link != "" {
+		g.P(
+			"ctx.Header(\"Link\", ",
+			strconv.Quote("<"+rpc.Deprecation.Link+">; rel=\"deprecation\""),
+			")",
+		)
+	}
+	g.P("if p.opts.deprecatedCallHook != nil {")
+	g.P("	p.opts.deprecatedCallHook(ctx, \"", rpc.FullMethod(), "\")")
+	g.P("}")
 }
 
+// renderQueryParameters binds the parameters from the query, or from the
+// form values of multipart requests with a src of PostForm, read through the
+// recv, either the gin context or the form values of a streamed multipart body
 func renderQueryParameters(
 	g *protogen.GeneratedFile,
 	prms []Parameter,
 	filter []string,
+	recv string,
+	src string,
+	opts Options,
 ) {
 	for _, prm := range prms {
 		found := false
@@ -194,18 +2765,18 @@ func renderQueryParameters(
 			}
 		}
 
-		if found || prm.IsPath {
+		if found || prm.IsPath || prm.In != "" || prm.ServerPopulated {
 			continue
 		}
 		if len(prm.Holding) != 0 {
 			g.P("body.", prm.FullParameter, " = &", prm.Field.Message.GoIdent, "{}")
-			renderQueryParameters(g, prm.Holding, filter)
+			renderQueryParameters(g, prm.Holding, filter, recv, src, opts)
 		} else {
 			if prm.IsList {
 				switch prm.Type {
 				case Int32Type:
 					g.P("{")
-					g.P("vals := ctx.QueryArray(\"", prm.RequestedKey, "\")")
+					g.P("vals := ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
 					g.P("fin := make([]int32, len(vals))")
 					g.P("for idx := range vals {")
 					g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(vals[idx], 10, 32)")
@@ -219,7 +2790,7 @@ func renderQueryParameters(
 					g.P("}")
 				case UInt32Type:
 					g.P("{")
-					g.P("vals := ctx.QueryArray(\"", prm.RequestedKey, "\")")
+					g.P("vals := ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
 					g.P("fin := make([]uint32, len(vals))")
 					g.P("for idx := range vals {")
 					g.P("p, err := ", strconvPackage.Ident("ParseUint"), "(vals[idx], 10, 32)")
@@ -233,7 +2804,7 @@ func renderQueryParameters(
 					g.P("}")
 				case Int64Type:
 					g.P("{")
-					g.P("vals := ctx.QueryArray(\"", prm.RequestedKey, "\")")
+					g.P("vals := ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
 					g.P("fin := make([]int64, len(vals))")
 					g.P("for idx := range vals {")
 					g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(vals[idx], 10, 64)")
@@ -247,7 +2818,7 @@ func renderQueryParameters(
 					g.P("}")
 				case UInt64Type:
 					g.P("{")
-					g.P("vals := ctx.QueryArray(\"", prm.RequestedKey, "\")")
+					g.P("vals := ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
 					g.P("fin := make([]uint64, len(vals))")
 					g.P("for idx := range vals {")
 					g.P("p, err := ", strconvPackage.Ident("ParseUint"), "(vals[idx], 10, 64)")
@@ -261,7 +2832,7 @@ func renderQueryParameters(
 					g.P("}")
 				case Float32Type:
 					g.P("{")
-					g.P("vals := ctx.QueryArray(\"", prm.RequestedKey, "\")")
+					g.P("vals := ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
 					g.P("fin := make([]float32, len(vals))")
 					g.P("for idx := range vals {")
 					g.P("p, err := ", strconvPackage.Ident("ParseFloat"), "(vals[idx], 32)")
@@ -275,7 +2846,7 @@ func renderQueryParameters(
 					g.P("}")
 				case Float64Type:
 					g.P("{")
-					g.P("vals := ctx.QueryArray(\"", prm.RequestedKey, "\")")
+					g.P("vals := ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
 					g.P("fin := make([]float64, len(vals))")
 					g.P("for idx := range vals {")
 					g.P("p, err := ", strconvPackage.Ident("ParseFloat"), "(vals[idx], 64)")
@@ -288,14 +2859,26 @@ func renderQueryParameters(
 					g.P("body.", prm.FullParameter, "= fin")
 					g.P("}")
 				case BytesType:
-					panic("bytes array currently not supported for query parameters")
+					g.P("{")
+					g.P("vals := ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
+					g.P("fin := make([][]byte, len(vals))")
+					g.P("for idx := range vals {")
+					g.P("p, err := decodeBytesParameter(vals[idx])")
+					g.P("if err != nil {")
+					g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+					g.P("	return")
+					g.P("}")
+					g.P("fin[idx] = p")
+					g.P("}")
+					g.P("body.", prm.FullParameter, "= fin")
+					g.P("}")
 				case EnumType:
 					g.P("{")
-					g.P("vals := ctx.QueryArray(\"", prm.RequestedKey, "\")")
+					g.P("vals := ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
 					g.P("fin := make([]", prm.Field.Enum.GoIdent, ", len(vals))")
 					g.P("for idx := range vals {")
-					g.P("p, ok := ", prm.Field.Enum.GoIdent, "_value[vals[idx]]")
-					g.P("if !ok {")
+					g.P("p, ok := parseEnumParameter(vals[idx], ", prm.Field.Enum.GoIdent, "_value)")
+					g.P("if !ok", rejectedEnumCondition(prm, opts), " {")
 					g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
 					g.P("	return")
 					g.P("}")
@@ -304,10 +2887,10 @@ func renderQueryParameters(
 					g.P("body.", prm.FullParameter, "= fin")
 					g.P("}")
 				case StringType:
-					g.P("body.", prm.FullParameter, "= ctx.QueryArray(\"", prm.RequestedKey, "\")")
+					g.P("body.", prm.FullParameter, "= ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
 				case BoolType:
 					g.P("{")
-					g.P("vals := ctx.QueryArray(\"", prm.RequestedKey, "\")")
+					g.P("vals := ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
 					g.P("fin := make([]bool, len(vals))")
 					g.P("for idx := range vals {")
 					g.P("p, err := ", strconvPackage.Ident("ParseBool"), "(vals[idx])")
@@ -321,7 +2904,7 @@ func renderQueryParameters(
 					g.P("}")
 				case TimeType:
 					g.P("{")
-					g.P("vals := ctx.QueryArray(\"", prm.RequestedKey, "\")")
+					g.P("vals := ", recv, ".", src, "Array(\"", prm.RequestedKey, "\")")
 
 					g.P("fin := make([]*", timepbPackage.Ident("Timestamp"), ", len(vals))")
 					g.P("for idx := range vals {")
@@ -336,163 +2919,252 @@ func renderQueryParameters(
 					g.P("}")
 				}
 			} else {
-				g.P("if val, ok := ctx.GetQuery(\"", prm.RequestedKey, "\"); ok {")
-
-				if !prm.IsOptional {
-					switch prm.Type {
-					case Int32Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(val, 10, 32)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= int32(p)")
-					case UInt32Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseUint"), "(val, 10, 32)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= uint32(p)")
-					case Int64Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(val, 10, 64)")
-						g.P("if err != nil {")
-						g.P("ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= p")
-					case UInt64Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseUint"), "(val, 10, 64)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= p")
-					case Float32Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseFloat"), "(val, 32)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= float32(p)")
-					case Float64Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseFloat"), "(val, 64)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= p")
-					case BytesType:
-						g.P("body.", prm.FullParameter, "= []byte(val)")
-					case EnumType:
-						g.P("p, ok := ", prm.Field.Enum.GoIdent, "_value[val]")
-						g.P("if !ok {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, " = ", prm.Field.Enum.GoIdent, "(p)")
-					case StringType:
-						g.P("body.", prm.FullParameter, "= val")
-					case BoolType:
-						g.P("p, err := ", strconvPackage.Ident("ParseBool"), "(val)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= p")
-					case TimeType:
-						g.P("p, err := ", timePackage.Ident("Parse"), "(", timePackage.Ident("RFC3339"), ", val)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= ", timepbPackage.Ident("New(p)"))
-					}
+				g.P("if val, ok := ", recv, ".Get", src, "(\"", prm.RequestedKey, "\"); ok {")
+				renderParameterParse(g, prm, opts)
+				if prm.Defaulted {
+					g.P("}")
+					continue
+				}
+				g.P("} else {")
+				if prm.IsOptional {
+					g.P("body.", prm.FullParameter, " = nil")
+				} else if recv == "form" {
+					g.P("ctx.Error(newMissingFormValuesError(\"", prm.RequestedKey, "\"))")
+					g.P("return")
 				} else {
-					switch prm.Type {
-					case Int32Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(val, 10, 32)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("x := int32(p)")
-						g.P("body.", prm.FullParameter, "= &x")
-					case UInt32Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseUint"), "(val, 10, 32)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("x := uint32(p)")
-						g.P("body.", prm.FullParameter, "= &x")
-					case Int64Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(val, 10, 64)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= &p")
-					case UInt64Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseUint"), "(val, 10, 64)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= &p")
-					case Float32Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseFloat"), "(val, 32)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("x := float32(p)")
-						g.P("body.", prm.FullParameter, "= &x")
-					case Float64Type:
-						g.P("p, err := ", strconvPackage.Ident("ParseFloat"), "(val, 64)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= &p")
-					case BytesType:
-						g.P("body.", prm.FullParameter, "= []byte(val)")
-					case EnumType:
-						g.P("p, ok := ", prm.Field.Enum.GoIdent, "_value[val]")
-						g.P("if !ok {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("x := ", prm.Field.Enum.GoIdent, "(p)")
-						g.P("body.", prm.FullParameter, "= &x")
-					case StringType:
-						g.P("body.", prm.FullParameter, "= &val")
-					case BoolType:
-						g.P("p, err := ", strconvPackage.Ident("ParseBool"), "(val)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= &p")
-					case TimeType:
-						g.P("p, err := ", timePackage.Ident("Parse"), "(", timePackage.Ident("RFC3339"), ", val)")
-						g.P("if err != nil {")
-						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
-						g.P("	return")
-						g.P("}")
-						g.P("body.", prm.FullParameter, "= ", timepbPackage.Ident("New(p)"))
-					}
+					g.P("ctx.Error(newMissingRequiredParametersError(\"", prm.RequestedKey, "\"))")
+					g.P("return")
 				}
+				g.P("}")
+			}
+		}
+	}
+}
+
+// renderServerFields clears the server populated fields of the input and
+// hands them to the populator
+func renderServerFields(
+	g *protogen.GeneratedFile,
+	rpc APIPath,
+) {
+	g.P("{")
+	g.P("fds := body.ProtoReflect().Descriptor().Fields()")
+	g.P("serverFields := []", protoreflectPackage.Ident("FieldDescriptor"), "{")
+	for _, field := range rpc.ServerFields() {
+		g.P("fds.ByName(", strconv.Quote(string(field.Desc.Name())), "),")
+	}
+	g.P("}")
+	g.P("for _, fd := range serverFields {")
+	g.P("	body.ProtoReflect().Clear(fd)")
+	g.P("}")
+	g.P("if p.opts.serverFieldPopulator != nil {")
+	g.P("if err := p.opts.serverFieldPopulator.PopulateServerFields(")
+	g.P("c,")
+	g.P(rpc.RouteInfoName(), ",")
+	g.P("&body,")
+	g.P("serverFields,")
+	g.P("); err != nil {")
+	g.P("	ctx.Error(err)")
+	g.P("	return")
+	g.P("}")
+	g.P("}")
+	g.P("}")
+}
+
+// renderHeaderParameters binds the top level parameters sourced from headers
+// and cookies, overriding anything sent in the body
+func renderHeaderParameters(
+	g *protogen.GeneratedFile,
+	prms []Parameter,
+	opts Options,
+) {
+	for _, prm := range prms {
+		switch prm.In {
+		case "header":
+			g.P("if val := ctx.GetHeader(\"", prm.RequestedKey, "\"); val != \"\" {")
+		case "cookie":
+			g.P("if val, err := ctx.Cookie(\"", prm.RequestedKey, "\"); err == nil {")
+		default:
+			continue
+		}
+		renderParameterParse(g, prm, opts)
+		g.P("} else {")
+		if prm.IsOptional {
+			g.P("body.", prm.FullParameter, " = nil")
+		} else {
+			g.P("ctx.Error(newMissingRequiredParametersError(\"", prm.RequestedKey, "\"))")
+			g.P("return")
+		}
+		g.P("}")
+	}
+}
 
-				g.P("} else {")
-				if prm.IsOptional {
-					g.P("body.", prm.FullParameter, " = nil")
-				} else {
-					g.P("ctx.Error(newMissingRequiredParametersError(\"", prm.RequestedKey, "\"))")
-					g.P("return")
-				}
-				g.P("}")
-			}
+// renderParameterParse parses the single value parameter in val into the
+// body, failing with an unparsable parameter error
+func renderParameterParse(
+	g *protogen.GeneratedFile,
+	prm Parameter,
+	opts Options,
+) {
+	if !prm.IsOptional {
+		switch prm.Type {
+		case Int32Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(val, 10, 32)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= int32(p)")
+		case UInt32Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseUint"), "(val, 10, 32)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= uint32(p)")
+		case Int64Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(val, 10, 64)")
+			g.P("if err != nil {")
+			g.P("ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= p")
+		case UInt64Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseUint"), "(val, 10, 64)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= p")
+		case Float32Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseFloat"), "(val, 32)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= float32(p)")
+		case Float64Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseFloat"), "(val, 64)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= p")
+		case BytesType:
+			g.P("p, err := decodeBytesParameter(val)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= p")
+		case EnumType:
+			g.P("p, ok := parseEnumParameter(val, ", prm.Field.Enum.GoIdent, "_value)")
+			g.P("if !ok", rejectedEnumCondition(prm, opts), " {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, " = ", prm.Field.Enum.GoIdent, "(p)")
+		case StringType:
+			g.P("body.", prm.FullParameter, "= val")
+		case BoolType:
+			g.P("p, err := ", strconvPackage.Ident("ParseBool"), "(val)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= p")
+		case TimeType:
+			g.P(
+				"p, err := ",
+				timePackage.Ident("Parse"),
+				"(",
+				timePackage.Ident("RFC3339"),
+				", val)",
+			)
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= ", timepbPackage.Ident("New(p)"))
+		}
+	} else {
+		switch prm.Type {
+		case Int32Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(val, 10, 32)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("x := int32(p)")
+			g.P("body.", prm.FullParameter, "= &x")
+		case UInt32Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseUint"), "(val, 10, 32)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("x := uint32(p)")
+			g.P("body.", prm.FullParameter, "= &x")
+		case Int64Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseInt"), "(val, 10, 64)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= &p")
+		case UInt64Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseUint"), "(val, 10, 64)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= &p")
+		case Float32Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseFloat"), "(val, 32)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("x := float32(p)")
+			g.P("body.", prm.FullParameter, "= &x")
+		case Float64Type:
+			g.P("p, err := ", strconvPackage.Ident("ParseFloat"), "(val, 64)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= &p")
+		case BytesType:
+			g.P("p, err := decodeBytesParameter(val)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= p")
+		case EnumType:
+			g.P("p, ok := parseEnumParameter(val, ", prm.Field.Enum.GoIdent, "_value)")
+			g.P("if !ok", rejectedEnumCondition(prm, opts), " {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("x := ", prm.Field.Enum.GoIdent, "(p)")
+			g.P("body.", prm.FullParameter, "= &x")
+		case StringType:
+			g.P("body.", prm.FullParameter, "= &val")
+		case BoolType:
+			g.P("p, err := ", strconvPackage.Ident("ParseBool"), "(val)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= &p")
+		case TimeType:
+			g.P("p, err := ", timePackage.Ident("Parse"), "(", timePackage.Ident("RFC3339"), ", val)")
+			g.P("if err != nil {")
+			g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+			g.P("	return")
+			g.P("}")
+			g.P("body.", prm.FullParameter, "= ", timepbPackage.Ident("New(p)"))
 		}
 	}
 }
@@ -501,6 +3173,7 @@ func renderPathParameters(
 	g *protogen.GeneratedFile,
 	prms []Parameter,
 	filter []string,
+	opts Options,
 ) {
 	for _, prm := range prms {
 		found := false
@@ -516,7 +3189,7 @@ func renderPathParameters(
 		}
 		if len(prm.Holding) != 0 {
 			// g.P("body.", prm.FullParameter, " = &", prm.Field.Message.GoIdent, "{}") // TODO: issues may arise here
-			renderPathParameters(g, prm.Holding, filter)
+			renderPathParameters(g, prm.Holding, filter, opts)
 		} else {
 			if !prm.IsPath {
 				continue
@@ -572,10 +3245,15 @@ func renderPathParameters(
 						g.P("}")
 						g.P("body.", prm.FullParameter, "= p")
 					case BytesType:
-						g.P("body.", prm.FullParameter, "= []byte(val)")
+						g.P("p, err := decodeBytesParameter(val)")
+						g.P("if err != nil {")
+						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+						g.P("	return")
+						g.P("}")
+						g.P("body.", prm.FullParameter, "= p")
 					case EnumType:
-						g.P("p, ok := ", prm.Field.Enum.GoIdent, "_value[val]")
-						g.P("if !ok {")
+						g.P("p, ok := parseEnumParameter(val, ", prm.Field.Enum.GoIdent, "_value)")
+						g.P("if !ok", rejectedEnumCondition(prm, opts), " {")
 						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
 						g.P("	return")
 						g.P("}")
@@ -645,10 +3323,15 @@ func renderPathParameters(
 						g.P("}")
 						g.P("body.", prm.FullParameter, "= &p")
 					case BytesType:
-						g.P("body.", prm.FullParameter, "= []byte(val)")
+						g.P("p, err := decodeBytesParameter(val)")
+						g.P("if err != nil {")
+						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
+						g.P("	return")
+						g.P("}")
+						g.P("body.", prm.FullParameter, "= p")
 					case EnumType:
-						g.P("p, ok := ", prm.Field.Enum.GoIdent, "_value[val]")
-						g.P("if !ok {")
+						g.P("p, ok := parseEnumParameter(val, ", prm.Field.Enum.GoIdent, "_value)")
+						g.P("if !ok", rejectedEnumCondition(prm, opts), " {")
 						g.P("	ctx.Error(newUnparsableParameterError(\"", prm.RequestedKey, "\"))")
 						g.P("	return")
 						g.P("}")
@@ -686,6 +3369,20 @@ func renderPathParameters(
 	}
 }
 
+// rejectedEnumCondition additional condition on which a parsed enum
+// parameter is rejected
+func rejectedEnumCondition(prm Parameter, opts Options) string {
+	if opts.RejectUnspecifiedEnums && isUnspecifiedEnumValue(prm.Field.Enum.Values[0]) {
+		return " || p == 0"
+	}
+	return ""
+}
+
+func isUnspecifiedEnumValue(val *protogen.EnumValue) bool {
+	return val.Desc.Number() == 0 &&
+		strings.HasSuffix(string(val.Desc.Name()), "_UNSPECIFIED")
+}
+
 type info struct {
 	Title   string `json:"title"`
 	Version string `json:"version"`
@@ -703,6 +3400,7 @@ func GenerateOpenAPI(
 	g *protogen.GeneratedFile,
 	gjson *protogen.GeneratedFile,
 	file *protogen.File,
+	opts Options,
 ) error {
 	g.P("openapi: 3.0.3")
 	g.P("info:")
@@ -729,12 +3427,61 @@ func GenerateOpenAPI(
 			}
 			g.P("      summary: ", api.Summary)         // TODO: escaping
 			g.P("      description: ", api.Description) // TODO: escaping
+			if api.Deprecation.Deprecated {
+				g.P("      deprecated: true")
+			}
+			if len(api.Features) != 0 {
+				g.P("      x-features:")
+				for _, feature := range api.Features {
+					g.P("        - ", strconv.Quote(feature))
+				}
+			}
+			if api.Pagination != nil {
+				g.P("      x-pagination:")
+				g.P("        page-size: ", opts.FieldName(api.Pagination.PageSize.Desc))
+				g.P("        page-token: ", opts.FieldName(api.Pagination.PageToken.Desc))
+				g.P("        next-page-token: ", opts.FieldName(api.Pagination.NextPageToken.Desc))
+				g.P("        max-page-size: ", api.Pagination.MaxPageSize)
+			}
+			if api.Timeout != nil && api.Timeout.Seconds != 0 {
+				g.P("      x-timeout: ", api.Timeout.Seconds)
+			}
+			if api.RateLimit != nil {
+				g.P("      x-ratelimit:")
+				g.P("        requests: ", api.RateLimit.Requests)
+				g.P("        window: ", api.RateLimit.WindowSeconds)
+				g.P("        burst: ", api.RateLimit.Burst)
+				g.P("        key: ", api.RateLimit.Key)
+				if api.RateLimit.Key == "header" {
+					g.P("        header: ", strconv.Quote(api.RateLimit.Header))
+				}
+			}
 
-			if api.HTTPMethod != "GET" && api.HTTPMethod != "DELETE" {
+			if api.Body != nil {
+				renderRawBodyOpenAPI(g, api, opts)
+			} else if api.HTTPMethod != "GET" && api.HTTPMethod != "DELETE" {
 				g.P("      parameters:")
-				renderParametersOpenAPI(g, api.Parameters, true, false)
+				renderParametersOpenAPI(g, api.Parameters, true, opts)
+				renderControllerParametersOpenAPI(g, api)
 				g.P("      requestBody:")
-				g.P("        description: ", api.Method.Input.GoIdent.GoName)
+				if api.UpdateMask != nil {
+					g.P(
+						"        description: ",
+						api.Method.Input.GoIdent.GoName,
+						" as a merge patch, fields present in the body",
+					)
+					g.P(
+						"          are updated, explicit nulls clear them and empty objects",
+					)
+					g.P(
+						"          change nothing. ",
+						opts.FieldName(api.UpdateMask.Desc),
+						" is derived from the fields present",
+					)
+					g.P("          when it is not set")
+				} else {
+					g.P("        description: ", api.Method.Input.GoIdent.GoName)
+				}
 				g.P("        content:")
 				g.P("          application/json:")
 				g.P("            schema:")
@@ -749,20 +3496,92 @@ func GenerateOpenAPI(
 				g.P("        required: true")
 			} else {
 				g.P("      parameters:")
-				renderParametersOpenAPI(g, api.Parameters, false, false)
+				renderParametersOpenAPI(g, api.Parameters, false, opts)
+				renderControllerParametersOpenAPI(g, api)
 			}
 
 			g.P("      responses:")
 			g.P("        '200':")
 			g.P("          description: ", api.Method.Output.GoIdent.GoName)
 			g.P("          content: ")
-			g.P("            application/json:")
-			g.P("              schema:")
-			g.P(
-				"                $ref: '#/components/schemas/",
-				api.Method.Output.GoIdent.GoName,
-				"'",
-			)
+			if api.RawResponse != nil {
+				g.P("            ", strconv.Quote(api.RawResponse.ContentType), ":")
+				g.P("              schema:")
+				g.P("                type: string")
+				g.P("                format: binary")
+			} else {
+				g.P("            application/json:")
+				g.P("              schema:")
+				g.P(
+					"                $ref: '#/components/schemas/",
+					api.OutputSchemaPrefix(opts)+api.Method.Output.GoIdent.GoName,
+					"'",
+				)
+			}
+			if api.Cache != nil || (api.Pagination != nil && api.HTTPMethod == "GET") ||
+				api.RawResponse != nil {
+				g.P("          headers:")
+			}
+			if api.RawResponse != nil {
+				g.P("            Content-Disposition:")
+				g.P("              schema:")
+				g.P("                type: string")
+				if api.RawResponse.Inline {
+					g.P("                example: inline; filename=file.bin")
+				} else {
+					g.P("                example: attachment; filename=file.bin")
+				}
+			}
+			if api.Pagination != nil && api.HTTPMethod == "GET" {
+				g.P("            Link:")
+				g.P("              description: Link to the next page, sent when there is one")
+				g.P("              schema:")
+				g.P("                type: string")
+			}
+			if api.Cache != nil {
+				g.P("            ETag:")
+				g.P("              schema:")
+				g.P("                type: string")
+				g.P("            Cache-Control:")
+				g.P("              schema:")
+				g.P("                type: string")
+				g.P("                example: ", strconv.Quote(api.Cache.Control()))
+				g.P("        '304':")
+				g.P("          description: NotModified")
+			}
+			if api.Concurrency != nil {
+				g.P("        '412':")
+				g.P("          description: PreconditionFailedError")
+				if api.Concurrency.Required {
+					g.P("        '428':")
+					g.P("          description: PreconditionRequiredError")
+				}
+			}
+			if api.Body != nil {
+				g.P("        '413':")
+				g.P("          description: RequestTooLargeError")
+				if api.Body.Multipart || len(api.Body.ContentTypes) != 0 {
+					g.P("        '415':")
+					g.P("          description: UnsupportedMediaTypeError")
+				}
+			}
+			if api.Idempotent {
+				g.P("        '409':")
+				g.P("          description: IdempotencyKeyConflictError")
+			}
+			if api.RateLimit != nil {
+				g.P("        '429':")
+				g.P("          description: TooManyRequestsError")
+				g.P("          headers:")
+				g.P("            Retry-After:")
+				g.P("              description: Seconds until the next call is allowed")
+				g.P("              schema:")
+				g.P("                type: integer")
+			}
+			if api.Timeout != nil {
+				g.P("        '504':")
+				g.P("          description: GatewayTimeoutError")
+			}
 
 		}
 	}
@@ -777,8 +3596,9 @@ func GenerateOpenAPI(
 				g,
 				schemas,
 				api.Method.Output,
-				"",
-				false,
+				api.OutputSchemaPrefix(opts),
+				api.EnumNumbers(opts),
+				opts,
 			); err != nil {
 				return err
 			}
@@ -787,7 +3607,7 @@ func GenerateOpenAPI(
 				g,
 				api.Method.Input.GoIdent.GoName,
 				api.Parameters,
-				false,
+				opts,
 			)
 
 			// if err := generateOpenAPIComponentSchema(
@@ -832,18 +3652,25 @@ func renderParametersOpenAPI(
 	g *protogen.GeneratedFile,
 	prms []Parameter,
 	skipQP bool,
-	skipUserContext bool,
+	opts Options,
 ) {
 	for _, prm := range prms {
+		if prm.ServerPopulated {
+			continue
+		}
 		if len(prm.Holding) != 0 {
-			if skipUserContext && prm.RequestedKey == "userContext" {
-				continue
-			}
-			renderParametersOpenAPI(g, prm.Holding, skipQP, false)
+			renderParametersOpenAPI(g, prm.Holding, skipQP, opts)
 		} else {
 
 			if prm.IsPath {
 				g.P("        - in: path")
+			} else if prm.In == "header" && strings.EqualFold(prm.RequestedKey, "Content-Type") {
+				// described by the content of the request body
+				continue
+			} else if prm.In == "header" || prm.In == "cookie" {
+				g.P("        - in: ", prm.In)
+			} else if prm.In != "" {
+				continue
 			} else {
 				if skipQP {
 					continue
@@ -852,7 +3679,10 @@ func renderParametersOpenAPI(
 			}
 
 			g.P("          name: ", prm.RequestedKey)
-			if !prm.IsOptional {
+			if isDeprecated(prm.Field.Desc) {
+				g.P("          deprecated: true")
+			}
+			if !prm.IsOptional && !prm.Defaulted {
 				g.P("          required: true")
 			} else {
 				g.P("          required: false")
@@ -873,16 +3703,19 @@ func renderParametersOpenAPI(
 				g.P(prfx, "            example: 1")
 			case UInt32Type:
 				g.P(prfx, "            type: integer")
-				g.P(prfx, "            format: int32")
+				g.P(prfx, "            format: int64")
+				g.P(prfx, "            minimum: 0")
 				g.P(prfx, "            example: 1")
 			case Int64Type:
-				g.P(prfx, "            type: integer")
+				g.P(prfx, "            type: string")
 				g.P(prfx, "            format: int64")
-				g.P(prfx, "            example: 1")
+				g.P(prfx, "            pattern: '^-?[0-9]+$'")
+				g.P(prfx, "            example: '1'")
 			case UInt64Type:
-				g.P(prfx, "            type: integer")
-				g.P(prfx, "            format: int64")
-				g.P(prfx, "            example: 1")
+				g.P(prfx, "            type: string")
+				g.P(prfx, "            format: uint64")
+				g.P(prfx, "            pattern: '^[0-9]+$'")
+				g.P(prfx, "            example: '1'")
 			case Float32Type:
 				g.P(prfx, "            type: number")
 				g.P(prfx, "            format: float")
@@ -894,16 +3727,15 @@ func renderParametersOpenAPI(
 			case BytesType:
 				g.P(prfx, "            type: string")
 				g.P(prfx, "            format: byte")
-				g.P(prfx, "            example: sample")
+				g.P(prfx, "            example: c2FtcGxl")
 			case EnumType:
-				g.P(prfx, "            example: 1")
-				g.P(prfx, "            type: string")
-
-				values := prm.Field.Enum.Values[0].Desc.Name()
-				for i := 1; i < len(prm.Field.Enum.Values); i++ {
-					values = values + ", " + prm.Field.Enum.Values[i].Desc.Name()
-				}
-				g.P(prfx, "            enum: [", values, "]")
+				renderEnumOpenAPI(
+					g,
+					prfx+"            ",
+					prm.Field.Enum,
+					opts.RejectUnspecifiedEnums,
+					false,
+				)
 			case StringType:
 				g.P(prfx, "            type: string")
 				g.P(prfx, "            example: sample")
@@ -919,6 +3751,102 @@ func renderParametersOpenAPI(
 	}
 }
 
+// renderRawBodyOpenAPI documents an rpc bound from a raw or multipart body,
+// the other parameters are read from the query or form values
+func renderRawBodyOpenAPI(g *protogen.GeneratedFile, api APIPath, opts Options) {
+	g.P("      parameters:")
+	renderParametersOpenAPI(g, api.Parameters, api.Body.Multipart, opts)
+	renderControllerParametersOpenAPI(g, api)
+	g.P("      requestBody:")
+	if api.Body.Multipart && api.Body.Stream {
+		g.P(
+			"        description: ",
+			api.Method.Input.GoIdent.GoName,
+			", the form values have to precede the file part",
+		)
+	} else {
+		g.P("        description: ", api.Method.Input.GoIdent.GoName)
+	}
+	g.P("        content:")
+	if api.Body.Multipart {
+		g.P("          multipart/form-data:")
+		g.P("            schema:")
+		g.P("              $ref: '#/components/schemas/", api.Method.Input.GoIdent.GoName, "'")
+	} else {
+		contentTypes := api.Body.ContentTypes
+		if len(contentTypes) == 0 {
+			contentTypes = []string{"application/octet-stream"}
+		}
+		for _, contentType := range contentTypes {
+			g.P("          ", strconv.Quote(contentType), ":")
+			g.P("            schema:")
+			g.P("              type: string")
+			g.P("              format: binary")
+		}
+	}
+	g.P("        required: true")
+}
+
+// renderControllerParametersOpenAPI parameters read by the controller of the
+// rpc rather than bound to its input
+func renderControllerParametersOpenAPI(g *protogen.GeneratedFile, api APIPath) {
+	if api.HasFieldsParameter() {
+		g.P("        - in: query")
+		g.P("          name: fields")
+		g.P("          required: false")
+		g.P("          description: Comma separated field paths to include in the response,")
+		g.P("            ex. id,items.name, all fields are included when not set")
+		g.P("          schema:")
+		g.P("            type: string")
+	}
+	if api.Idempotent {
+		g.P("        - in: header")
+		g.P("          name: Idempotency-Key")
+		g.P("          required: false")
+		g.P("          description: Repeated calls with the same key are answered with the")
+		g.P("            response of the first call, reusing a key with a different request")
+		g.P("            responds with 409")
+		g.P("          schema:")
+		g.P("            type: string")
+	}
+	if api.Concurrency != nil {
+		g.P("        - in: header")
+		g.P("          name: If-Match")
+		g.P("          required: ", api.Concurrency.Required)
+		g.P("          description: ETag of the version of the resource being modified,")
+		g.P("            responds with 412 when it is no longer current")
+		g.P("          schema:")
+		g.P("            type: string")
+	}
+	if api.Timeout != nil && api.Timeout.FromHeaders {
+		capped := ""
+		if api.Timeout.Seconds != 0 {
+			capped = fmt.Sprintf(", capped at %d seconds", api.Timeout.Seconds)
+		}
+		g.P("        - in: header")
+		g.P("          name: grpc-timeout")
+		g.P("          required: false")
+		g.P("          description: Deadline of the call in the grpc format, ex. 500m", capped)
+		g.P("          schema:")
+		g.P("            type: string")
+		g.P("        - in: header")
+		g.P("          name: Request-Timeout")
+		g.P("          required: false")
+		g.P("          description: Deadline of the call in seconds", capped)
+		g.P("          schema:")
+		g.P("            type: number")
+	}
+	if api.Cache != nil {
+		g.P("        - in: header")
+		g.P("          name: If-None-Match")
+		g.P("          required: false")
+		g.P("          description: ETag of a cached response, responds with 304 when")
+		g.P("            it is still current")
+		g.P("          schema:")
+		g.P("            type: string")
+	}
+}
+
 func renderRequestBodyOpenAPI(
 	g *protogen.GeneratedFile,
 	prms []Parameter,
@@ -950,16 +3878,19 @@ func renderRequestBodyOpenAPI(
 				g.P(prefix, prfx, "                     example: 1")
 			case UInt32Type:
 				g.P(prefix, prfx, "                     type: integer")
-				g.P(prefix, prfx, "                     format: int32")
+				g.P(prefix, prfx, "                     format: int64")
+				g.P(prefix, prfx, "                     minimum: 0")
 				g.P(prefix, prfx, "                     example: 1")
 			case Int64Type:
-				g.P(prefix, prfx, "                     type: integer")
+				g.P(prefix, prfx, "                     type: string")
 				g.P(prefix, prfx, "                     format: int64")
-				g.P(prefix, prfx, "                     example: 1")
+				g.P(prefix, prfx, "                     pattern: '^-?[0-9]+$'")
+				g.P(prefix, prfx, "                     example: '1'")
 			case UInt64Type:
-				g.P(prefix, prfx, "                     type: integer")
-				g.P(prefix, prfx, "                     format: int64")
-				g.P(prefix, prfx, "                     example: 1")
+				g.P(prefix, prfx, "                     type: string")
+				g.P(prefix, prfx, "                     format: uint64")
+				g.P(prefix, prfx, "                     pattern: '^[0-9]+$'")
+				g.P(prefix, prfx, "                     example: '1'")
 			case Float32Type:
 				g.P(prefix, prfx, "                     type: number")
 				g.P(prefix, prfx, "                     format: float")
@@ -971,18 +3902,15 @@ func renderRequestBodyOpenAPI(
 			case BytesType:
 				g.P(prefix, prfx, "                     type: string")
 				g.P(prefix, prfx, "                     format: byte")
-				g.P(prefix, prfx, "                     example: sample")
+				g.P(prefix, prfx, "                     example: c2FtcGxl")
 			case EnumType:
-				g.P(prefix, prfx, "                     type: integer")
-				g.P(prefix, prfx, "                     format: int32")
-				g.P(prefix, prfx, "                     example: 1")
-				g.P(prefix, prfx, "                     type: string")
-
-				values := prm.Field.Enum.Values[0].Desc.Name()
-				for i := 1; i < len(prm.Field.Enum.Values); i++ {
-					values = values + ", " + prm.Field.Enum.Values[i].Desc.Name()
-				}
-				g.P(prefix, prfx, "                     enum: [", values, "]")
+				renderEnumOpenAPI(
+					g,
+					prefix+prfx+"                     ",
+					prm.Field.Enum,
+					false,
+					false,
+				)
 			case StringType:
 				g.P(prefix, prfx, "                     type: string")
 				g.P(prefix, prfx, "                     example: sample")
@@ -998,6 +3926,75 @@ func renderRequestBodyOpenAPI(
 	}
 }
 
+// renderEnumOpenAPI renders an enum schema, describing each of the values
+// through x-enum-descriptions, enums written as numbers list their names
+// through x-enum-varnames
+func renderEnumOpenAPI(
+	g *protogen.GeneratedFile,
+	indent string,
+	enum *protogen.Enum,
+	skipUnspecified bool,
+	numbers bool,
+) {
+	values := []string{}
+	names := []string{}
+	descriptions := []string{}
+	deprecated := []string{}
+	for _, val := range enum.Values {
+		if skipUnspecified && isUnspecifiedEnumValue(val) {
+			continue
+		}
+		value := string(val.Desc.Name())
+		if numbers {
+			value = strconv.Itoa(int(val.Desc.Number()))
+		}
+		values = append(values, value)
+		names = append(names, string(val.Desc.Name()))
+		if isDeprecated(val.Desc) {
+			deprecated = append(deprecated, value)
+		}
+		desc := strings.TrimSpace(string(val.Comments.Leading))
+		if desc == "" {
+			desc = strings.TrimSpace(string(val.Comments.Trailing))
+		}
+		descriptions = append(descriptions, strconv.Quote(desc))
+	}
+
+	if numbers {
+		g.P(indent, "type: integer")
+		g.P(indent, "format: int32")
+	} else {
+		g.P(indent, "type: string")
+	}
+	g.P(indent, "enum: [", strings.Join(values, ", "), "]")
+	if len(values) != 0 {
+		g.P(indent, "example: ", values[0])
+	}
+	if numbers {
+		g.P(indent, "x-enum-varnames: [", strings.Join(names, ", "), "]")
+	}
+	g.P(indent, "x-enum-descriptions:")
+	for _, desc := range descriptions {
+		g.P(indent, "  - ", desc)
+	}
+	if len(deprecated) != 0 {
+		g.P(indent, "x-enum-deprecated: [", strings.Join(deprecated, ", "), "]")
+	}
+}
+
+// isDeprecated checks if the deprecated option is set on a descriptor
+func isDeprecated(desc protoreflect.Descriptor) bool {
+	switch opts := desc.Options().(type) {
+	case *descriptorpb.MethodOptions:
+		return opts.GetDeprecated()
+	case *descriptorpb.FieldOptions:
+		return opts.GetDeprecated()
+	case *descriptorpb.EnumValueOptions:
+		return opts.GetDeprecated()
+	}
+	return false
+}
+
 func ToPrivateName(in string) (out string) {
 	inr := []rune(in)
 	inr[0] = unicode.ToLower(inr[0])
@@ -1010,7 +4007,8 @@ func generateOpenAPIComponentSchema(
 	s map[string]struct{},
 	m *protogen.Message,
 	keyPrefix string,
-	skipUserContext bool,
+	enumNumbers bool,
+	opts Options,
 ) error {
 	foundMessages := []*protogen.Message{}
 	if _, ok := s[keyPrefix+m.GoIdent.GoName]; !ok {
@@ -1021,11 +4019,10 @@ func generateOpenAPIComponentSchema(
 
 		for _, fld := range m.Fields {
 			field := fld
-
-			if skipUserContext && field.Desc.JSONName() == "userContext" {
-				continue
+			g.P("        ", opts.FieldName(field.Desc), ":")
+			if isDeprecated(field.Desc) {
+				g.P("          deprecated: true")
 			}
-			g.P("        ", field.Desc.JSONName(), ":")
 
 			prfx := ""
 			if field.Desc.IsMap() {
@@ -1049,35 +4046,38 @@ func generateOpenAPIComponentSchema(
 			case protoreflect.BoolKind:
 				g.P(prfx, "          type: boolean")
 				g.P(prfx, "          example: false")
-			case protoreflect.EnumKind: // TODO
-				g.P(prfx, "          type: string")
-
-				values := field.Enum.Values[0].Desc.Name()
-				for i := 1; i < len(field.Enum.Values); i++ {
-					values = values + ", " + field.Enum.Values[i].Desc.Name()
-				}
-				g.P(prfx, "          enum: [", values, "]")
+			case protoreflect.EnumKind:
+				renderEnumOpenAPI(g, prfx+"          ", field.Enum, false, enumNumbers)
 			case protoreflect.Int32Kind,
 				protoreflect.Sint32Kind,
-				protoreflect.Uint32Kind:
+				protoreflect.Sfixed32Kind:
 				g.P(prfx, "          type: integer")
 				g.P(prfx, "          format: int32")
 				g.P(prfx, "          example: 1")
-			case protoreflect.Int64Kind,
-				protoreflect.Sint64Kind,
-				protoreflect.Uint64Kind:
+			case protoreflect.Uint32Kind,
+				protoreflect.Fixed32Kind:
 				g.P(prfx, "          type: integer")
 				g.P(prfx, "          format: int64")
+				g.P(prfx, "          minimum: 0")
 				g.P(prfx, "          example: 1")
-			case protoreflect.Sfixed32Kind,
-				protoreflect.Fixed32Kind,
-				protoreflect.FloatKind:
+			case protoreflect.Int64Kind,
+				protoreflect.Sint64Kind,
+				protoreflect.Sfixed64Kind:
+				g.P(prfx, "          type: string")
+				g.P(prfx, "          format: int64")
+				g.P(prfx, "          pattern: '^-?[0-9]+$'")
+				g.P(prfx, "          example: '1'")
+			case protoreflect.Uint64Kind,
+				protoreflect.Fixed64Kind:
+				g.P(prfx, "          type: string")
+				g.P(prfx, "          format: uint64")
+				g.P(prfx, "          pattern: '^[0-9]+$'")
+				g.P(prfx, "          example: '1'")
+			case protoreflect.FloatKind:
 				g.P(prfx, "          type: number")
 				g.P(prfx, "          format: float")
 				g.P(prfx, "          example: 1.0")
-			case protoreflect.Sfixed64Kind,
-				protoreflect.Fixed64Kind,
-				protoreflect.DoubleKind:
+			case protoreflect.DoubleKind:
 				g.P(prfx, "          type: number")
 				g.P(prfx, "          format: double")
 				g.P(prfx, "          example: 1.0")
@@ -1087,7 +4087,7 @@ func generateOpenAPIComponentSchema(
 			case protoreflect.BytesKind:
 				g.P(prfx, "          type: string")
 				g.P(prfx, "          format: byte")
-				g.P(prfx, "          example: false")
+				g.P(prfx, "          example: c2FtcGxl")
 			case protoreflect.MessageKind:
 				if field.Message.Desc.FullName() == "google.protobuf.Timestamp" {
 					g.P(prfx, "          type: string")
@@ -1106,7 +4106,7 @@ func generateOpenAPIComponentSchema(
 	}
 
 	for _, found := range foundMessages {
-		generateOpenAPIComponentSchema(g, s, found, keyPrefix, false)
+		generateOpenAPIComponentSchema(g, s, found, keyPrefix, enumNumbers, opts)
 	}
 	return nil
 }
@@ -1115,7 +4115,7 @@ func generateOpenAPIComponentSchemaFromParameters(
 	g *protogen.GeneratedFile,
 	key string,
 	prms []Parameter,
-	skipUserContext bool,
+	opts Options,
 ) {
 	foundMessages := []Parameter{}
 	g.P("    ", key, ":")
@@ -1123,18 +4123,23 @@ func generateOpenAPIComponentSchemaFromParameters(
 	g.P("      properties:")
 	for idx := range prms {
 
-		if prms[idx].IsPath {
+		if prms[idx].In == "form" {
+			g.P("        ", prms[idx].RequestedKey, ":")
+			g.P("          type: string")
+			g.P("          format: binary")
+			continue
+		}
+		if prms[idx].IsPath || prms[idx].In != "" || prms[idx].ServerPopulated {
 			continue
 		}
 
 		field := prms[idx].Field
 
-		if skipUserContext && field.Desc.JSONName() == "userContext" {
-			continue
+		g.P("        ", opts.FieldName(field.Desc), ":")
+		if isDeprecated(field.Desc) {
+			g.P("          deprecated: true")
 		}
 
-		g.P("        ", field.Desc.JSONName(), ":")
-
 		prfx := ""
 		if field.Desc.IsMap() {
 			for _, f := range field.Message.Fields {
@@ -1157,35 +4162,38 @@ func generateOpenAPIComponentSchemaFromParameters(
 		case protoreflect.BoolKind:
 			g.P(prfx, "          type: boolean")
 			g.P(prfx, "          example: false")
-		case protoreflect.EnumKind: // TODO
-			g.P(prfx, "          type: string")
-
-			values := field.Enum.Values[0].Desc.Name()
-			for i := 1; i < len(field.Enum.Values); i++ {
-				values = values + ", " + field.Enum.Values[i].Desc.Name()
-			}
-			g.P(prfx, "          enum: [", values, "]")
+		case protoreflect.EnumKind:
+			renderEnumOpenAPI(g, prfx+"          ", field.Enum, false, false)
 		case protoreflect.Int32Kind,
 			protoreflect.Sint32Kind,
-			protoreflect.Uint32Kind:
+			protoreflect.Sfixed32Kind:
 			g.P(prfx, "          type: integer")
 			g.P(prfx, "          format: int32")
 			g.P(prfx, "          example: 1")
-		case protoreflect.Int64Kind,
-			protoreflect.Sint64Kind,
-			protoreflect.Uint64Kind:
+		case protoreflect.Uint32Kind,
+			protoreflect.Fixed32Kind:
 			g.P(prfx, "          type: integer")
 			g.P(prfx, "          format: int64")
+			g.P(prfx, "          minimum: 0")
 			g.P(prfx, "          example: 1")
-		case protoreflect.Sfixed32Kind,
-			protoreflect.Fixed32Kind,
-			protoreflect.FloatKind:
+		case protoreflect.Int64Kind,
+			protoreflect.Sint64Kind,
+			protoreflect.Sfixed64Kind:
+			g.P(prfx, "          type: string")
+			g.P(prfx, "          format: int64")
+			g.P(prfx, "          pattern: '^-?[0-9]+$'")
+			g.P(prfx, "          example: '1'")
+		case protoreflect.Uint64Kind,
+			protoreflect.Fixed64Kind:
+			g.P(prfx, "          type: string")
+			g.P(prfx, "          format: uint64")
+			g.P(prfx, "          pattern: '^[0-9]+$'")
+			g.P(prfx, "          example: '1'")
+		case protoreflect.FloatKind:
 			g.P(prfx, "          type: number")
 			g.P(prfx, "          format: float")
 			g.P(prfx, "          example: 1.0")
-		case protoreflect.Sfixed64Kind,
-			protoreflect.Fixed64Kind,
-			protoreflect.DoubleKind:
+		case protoreflect.DoubleKind:
 			g.P(prfx, "          type: number")
 			g.P(prfx, "          format: double")
 			g.P(prfx, "          example: 1.0")
@@ -1195,7 +4203,7 @@ func generateOpenAPIComponentSchemaFromParameters(
 		case protoreflect.BytesKind:
 			g.P(prfx, "          type: string")
 			g.P(prfx, "          format: byte")
-			g.P(prfx, "          example: false")
+			g.P(prfx, "          example: c2FtcGxl")
 		case protoreflect.MessageKind:
 			if field.Message.Desc.FullName() == "google.protobuf.Timestamp" {
 				g.P(prfx, "          type: string")
@@ -1217,7 +4225,7 @@ func generateOpenAPIComponentSchemaFromParameters(
 			g,
 			key+found.Field.GoName,
 			found.Holding,
-			false,
+			opts,
 		)
 	}
 }
@@ -1235,6 +4243,10 @@ func GeneratePermisionMaps(
 			rfs := map[string]interface{}{}
 			rfs["Roles"] = rpc.Roles
 			rfs["Features"] = rpc.Features
+			rfs["FullMethod"] = rpc.FullMethod()
+			rfs["HTTPMethod"] = rpc.HTTPMethod
+			rfs["Path"] = rpc.OpenAPIPath
+			rfs["Anonymous"] = rpc.Anonymous
 			cnqs[rpc.Method.Input.GoIdent.GoName] = rfs
 		}
 		resources[srv.Service.GoName] = cnqs
@@ -1248,3 +4260,89 @@ func GeneratePermisionMaps(
 
 	return nil
 }
+
+// GeneratePermissionRegistry generates typed role and feature constants
+// along with a registry of the routes of each service
+func GeneratePermissionRegistry(
+	srvs []Server,
+	g *protogen.GeneratedFile,
+) error {
+	roles := []string{}
+	features := []string{}
+	idents := map[string]string{}
+	for _, srv := range srvs {
+		for _, rpc := range srv.Paths {
+			for _, names := range []struct {
+				typ    string
+				vals   []string
+				target *[]string
+			}{
+				{"PermissionRole", rpc.Roles, &roles},
+				{"PermissionFeature", rpc.Features, &features},
+			} {
+				for _, name := range names.vals {
+					ident := permissionIdent(names.typ, name)
+					if ident == names.typ {
+						return fmt.Errorf("%s is not a valid role or feature name", name)
+					}
+					if existing, ok := idents[ident]; ok {
+						if existing != name {
+							return fmt.Errorf(
+								"%s and %s both map to the constant %s",
+								existing,
+								name,
+								ident,
+							)
+						}
+						continue
+					}
+					idents[ident] = name
+					*names.target = append(*names.target, name)
+				}
+			}
+		}
+	}
+
+	g.P("// PermissionRole role declared in the documentation of an rpc")
+	g.P("type PermissionRole string")
+	g.P("")
+	g.P("// PermissionFeature feature declared in the documentation of an rpc")
+	g.P("type PermissionFeature string")
+	g.P("")
+	if len(roles) != 0 {
+		g.P("const (")
+		for _, role := range roles {
+			g.P(permissionIdent("PermissionRole", role), " PermissionRole = ", strconv.Quote(role))
+		}
+		g.P(")")
+		g.P("")
+	}
+	if len(features) != 0 {
+		g.P("const (")
+		for _, feature := range features {
+			g.P(
+				permissionIdent("PermissionFeature", feature),
+				" PermissionFeature = ",
+				strconv.Quote(feature),
+			)
+		}
+		g.P(")")
+		g.P("")
+	}
+
+	for _, srv := range srvs {
+		g.P(
+			"// ",
+			srv.Service.GoName,
+			"HTTPRoutes route information of every rpc in ",
+			srv.Service.GoName,
+		)
+		g.P("var ", srv.Service.GoName, "HTTPRoutes = []*HTTPRouteInfo{")
+		for _, rpc := range srv.Paths {
+			g.P(rpc.RouteInfoName(), ",")
+		}
+		g.P("}")
+		g.P("")
+	}
+	return nil
+}
@@ -1,13 +1,134 @@
 package pkg
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BetaLixT/golang-tooling/protoc-gen-goblthttp/custom/annotations"
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 type Server struct {
 	Service *protogen.Service
 	Paths   []APIPath
+	// CORS cross origin policy of the service, nil when cross origin calls are
+	// not handled
+	CORS *CORS
+}
+
+// CORS cross origin resource sharing policy of a service
+type CORS struct {
+	AllowedOrigins   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAgeSeconds    uint32
+}
+
+// CORSPolicyName name of the generated cors policy variable of the service
+func (s *Server) CORSPolicyName() string {
+	return "_" + s.Service.GoName + "_HTTPCORSPolicy"
+}
+
+// RouteMethods route templates of the service in the order they are first
+// registered, along with the http methods registered on each
+func (s *Server) RouteMethods() ([]string, map[string][]string) {
+	routes := []string{}
+	methods := map[string][]string{}
+	for _, pth := range s.Paths {
+		if _, ok := methods[pth.GoPath]; !ok {
+			routes = append(routes, pth.GoPath)
+		}
+		methods[pth.GoPath] = appendHeader(methods[pth.GoPath], pth.HTTPMethod)
+	}
+	return routes, methods
+}
+
+// CORSAllowedHeaders request headers read by the rpcs of the service along
+// with the ones allowed by its policy
+func (s *Server) CORSAllowedHeaders() []string {
+	headers := []string{"Content-Type"}
+	for _, pth := range s.Paths {
+		for _, prm := range pth.Parameters {
+			if prm.In == "header" {
+				headers = appendHeader(headers, prm.RequestedKey)
+			}
+		}
+		if pth.RateLimit != nil && pth.RateLimit.Key == "header" {
+			headers = appendHeader(headers, pth.RateLimit.Header)
+		}
+		if pth.Idempotent {
+			headers = appendHeader(headers, "Idempotency-Key")
+		}
+		if pth.Concurrency != nil {
+			headers = appendHeader(headers, "If-Match")
+		}
+		if pth.Cache != nil {
+			headers = appendHeader(headers, "If-None-Match")
+		}
+		if pth.Timeout != nil && pth.Timeout.FromHeaders {
+			headers = appendHeader(headers, "grpc-timeout", "Request-Timeout")
+		}
+	}
+	return appendHeader(headers, s.CORS.AllowedHeaders...)
+}
+
+// CORSExposedHeaders response headers sent by the rpcs of the service along
+// with the ones exposed by its policy
+func (s *Server) CORSExposedHeaders() []string {
+	headers := []string{}
+	for _, pth := range s.Paths {
+		if pth.Deprecation.Deprecated {
+			headers = appendHeader(headers, "Deprecation")
+			if !pth.Deprecation.Sunset.IsZero() {
+				headers = appendHeader(headers, "Sunset")
+			}
+			if pth.Deprecation.Link != "" {
+				headers = appendHeader(headers, "Link")
+			}
+		}
+		if pth.RateLimit != nil {
+			headers = appendHeader(
+				headers,
+				"RateLimit-Limit",
+				"RateLimit-Remaining",
+				"RateLimit-Reset",
+				"Retry-After",
+			)
+		}
+		if pth.Idempotent {
+			headers = appendHeader(headers, "Idempotent-Replayed")
+		}
+		if pth.Cache != nil || (pth.Concurrency != nil && pth.Concurrency.ResponseField != nil) {
+			headers = appendHeader(headers, "ETag")
+		}
+		if pth.Pagination != nil && pth.HTTPMethod == "GET" {
+			headers = appendHeader(headers, "Link")
+		}
+	}
+	return appendHeader(headers, s.CORS.ExposedHeaders...)
+}
+
+// appendHeader appends the header names missing from the list, comparing them
+// case insensitively
+func appendHeader(headers []string, names ...string) []string {
+	for _, name := range names {
+		found := false
+		for idx := range headers {
+			if strings.EqualFold(headers[idx], name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			headers = append(headers, name)
+		}
+	}
+	return headers
 }
 
 // APIPath each rpc
@@ -22,15 +143,276 @@ type APIPath struct {
 	OpenAPIPath string
 	HTTPMethod  string
 	Parameters  []Parameter
+	Deprecation Deprecation
+	Anonymous   bool
+	RateLimit   *RateLimit
+	Idempotent  bool
+	Cache       *Cache
+	Concurrency *Concurrency
+	Pagination  *Pagination
+	// UpdateMask field mask of a PATCH rpc derived from the body when not set
+	UpdateMask *protogen.Field
+	// JSON json marshaling options of the rpc when they differ from the file
+	JSON *JSONOptions
+	// Timeout deadline of the rpc, if any
+	Timeout *Timeout
+	// Body raw request body of the rpc, nil when the body is json
+	Body *Body
+	// RawResponse raw response body of the rpc, nil when the response is json
+	RawResponse *RawResponse
+}
+
+// Body raw or multipart request body of an rpc
+type Body struct {
+	// Field bytes field of the input filled with the body, nil when the body
+	// is multipart. When streamed it is the raw body or form file field read
+	// through its generated reader
+	Field        *protogen.Field
+	Multipart    bool
+	Stream       bool
+	ContentTypes []string
+	MaxBytes     uint64
+}
+
+// RawResponse raw response body of an rpc
+type RawResponse struct {
+	// Field bytes field of the output written as the body
+	Field            *protogen.Field
+	ContentTypeField *protogen.Field
+	FilenameField    *protogen.Field
+	// ContentType of responses without a content type field, or when it is
+	// empty
+	ContentType string
+	Inline      bool
+}
+
+// Timeout deadline applied to calls of an rpc
+type Timeout struct {
+	// Seconds longest an rpc may run for, caps deadlines from headers, 0 when
+	// uncapped
+	Seconds uint32
+	// FromHeaders honors the grpc-timeout and Request-Timeout headers
+	FromHeaders bool
+}
+
+// MarshalOptionsName name of the generated json marshal options used for the
+// responses of the rpc
+func (r *APIPath) MarshalOptionsName() string {
+	if r.JSON == nil {
+		return "protomarsh"
+	}
+	return "_" + r.Method.Parent.GoName + "_" + r.Method.GoName + "_JSONMarshalOptions"
+}
+
+// EnumNumbers whether enums are written as numbers in the responses of the rpc
+func (r *APIPath) EnumNumbers(opts Options) bool {
+	if r.JSON == nil {
+		return opts.JSON.EnumNumbers
+	}
+	return r.JSON.EnumNumbers
+}
+
+// OutputSchemaPrefix prefix of the OpenAPI schema names of the output of the
+// rpc and the messages it holds, rpcs writing enums differently from the file
+// get schemas of their own
+func (r *APIPath) OutputSchemaPrefix(opts Options) string {
+	if r.EnumNumbers(opts) == opts.JSON.EnumNumbers {
+		return ""
+	}
+	if r.EnumNumbers(opts) {
+		return "EnumNumbers"
+	}
+	return "EnumNames"
+}
+
+// Pagination AIP-158 pagination of a query
+type Pagination struct {
+	PageSize      *protogen.Field
+	PageToken     *protogen.Field
+	NextPageToken *protogen.Field
+	MaxPageSize   uint32
+}
+
+// Concurrency optimistic concurrency control of a command through If-Match
+type Concurrency struct {
+	// Field field of the input populated from If-Match
+	Field *protogen.Field
+	// ResponseField field of the output sent back as the ETag, if any
+	ResponseField *protogen.Field
+	Required      bool
+}
+
+// Cache http caching of the responses of a query
+type Cache struct {
+	MaxAgeSeconds uint32
+	Private       bool
+	Vary          []string
+	// ETagField field of the output holding its version, responses are hashed
+	// for their ETag when nil
+	ETagField *protogen.Field
+}
+
+// Control value of the Cache-Control header of responses
+func (c *Cache) Control() string {
+	visibility := "public"
+	if c.Private {
+		visibility = "private"
+	}
+	if c.MaxAgeSeconds == 0 {
+		return visibility + ", no-cache"
+	}
+	return fmt.Sprintf("%s, max-age=%d", visibility, c.MaxAgeSeconds)
+}
+
+// ETagField finds the top level field of the message marked as its etag
+func ETagField(msg *protogen.Message) (*protogen.Field, error) {
+	var found *protogen.Field
+	for _, field := range msg.Fields {
+		fieldOpts, _ := proto.GetExtension(
+			field.Desc.Options(),
+			annotations.E_Field,
+		).(*annotations.Field)
+		if !fieldOpts.GetEtag() {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("multiple etag fields in %s", msg.Desc.FullName())
+		}
+		switch field.Desc.Kind() {
+		case protoreflect.StringKind, protoreflect.Int32Kind, protoreflect.Int64Kind,
+			protoreflect.Uint32Kind, protoreflect.Uint64Kind:
+		default:
+			return nil, fmt.Errorf(
+				"etag field %s has to be a string or integer",
+				field.Desc.FullName(),
+			)
+		}
+		if field.Desc.IsList() {
+			return nil, fmt.Errorf("etag field %s can not be repeated", field.Desc.FullName())
+		}
+		found = field
+	}
+	return found, nil
+}
+
+// RateLimit rate limit applied to calls of an rpc
+type RateLimit struct {
+	Requests      uint32
+	WindowSeconds uint32
+	Burst         uint32
+	// Key what calls are grouped by, one of ip, user or header
+	Key    string
+	Header string
+}
+
+// RouteInfoName name of the generated route info variable of the rpc
+func (r *APIPath) RouteInfoName() string {
+	return "_" + r.Method.Parent.GoName + "_" + r.Method.GoName + "_HTTPRouteInfo"
 }
 
-// BuildParameters builds parameters
-func (r *APIPath) BuildParameters(pathKeys map[string]string) {
-	r.Parameters = parseParameters(r.Method.Input, pathKeys, "", "")
-	pathCount := countPathParameters(r.Parameters)
-	if pathCount != len(pathKeys) {
-		panic("unmatched path keys (some of the path keys do not match body parameters)")
+// FullMethod full rpc name in the format used by grpc, /package.Service/Method
+func (r *APIPath) FullMethod() string {
+	return fmt.Sprintf(
+		"/%s/%s",
+		r.Method.Parent.Desc.FullName(),
+		r.Method.Desc.Name(),
+	)
+}
+
+// Deprecation deprecation details of an rpc
+type Deprecation struct {
+	Deprecated bool
+	Since      time.Time
+	Sunset     time.Time
+	Link       string
+}
+
+// BuildParameters builds parameters, failing on annotations binding fields
+// they can not be bound to
+func (r *APIPath) BuildParameters(pathKeys map[string]string, opts Options) error {
+	prms, err := parseParameters(r.Method.Input, pathKeys, "", "", opts)
+	if err != nil {
+		return err
 	}
+	r.Parameters = prms
+	if unmatched := unmatchedPathKeys(r.Parameters, pathKeys); len(unmatched) != 0 {
+		return fmt.Errorf(
+			"path keys do not match any field of the input: %s",
+			strings.Join(unmatched, ", "),
+		)
+	}
+	for _, prm := range ResourceParameters(r.Parameters) {
+		switch prm.Type {
+		case StringType, Int32Type, UInt32Type, Int64Type, UInt64Type:
+		default:
+			return fmt.Errorf(
+				"resource identifiers have to be strings or integers: %s",
+				prm.RequestedKey,
+			)
+		}
+		if prm.IsList {
+			return fmt.Errorf("resource identifiers can not be repeated: %s", prm.RequestedKey)
+		}
+	}
+	return nil
+}
+
+// IsCommand checks if the rpc takes a command (write) rather than a query
+// (read) as input
+func (r *APIPath) IsCommand() bool {
+	return strings.Contains(r.Method.Input.GoIdent.GoName, "Command")
+}
+
+// HasFieldsParameter checks if the rpc accepts a fields query parameter
+// selecting the fields of the response, queries do unless their input has a
+// field of the same name or their response is raw
+func (r *APIPath) HasFieldsParameter() bool {
+	if r.IsCommand() || r.RawResponse != nil {
+		return false
+	}
+	for _, field := range r.Method.Input.Fields {
+		if field.Desc.JSONName() == "fields" || field.Desc.Name() == "fields" {
+			return false
+		}
+	}
+	return true
+}
+
+// ServerFields top level fields of the input populated by the server
+func (r *APIPath) ServerFields() []*protogen.Field {
+	found := []*protogen.Field{}
+	for _, prm := range r.Parameters {
+		if prm.ServerPopulated {
+			found = append(found, prm.Field)
+		}
+	}
+	return found
+}
+
+// ResourceParameters parameters marked as resource identifiers, excluding
+// the ones within repeated messages
+func ResourceParameters(prms []Parameter) []Parameter {
+	found := []Parameter{}
+	for _, prm := range prms {
+		if len(prm.Holding) != 0 {
+			if prm.IsList {
+				continue
+			}
+			found = append(found, ResourceParameters(prm.Holding)...)
+		} else if prm.ResourceType != "" {
+			found = append(found, prm)
+		}
+	}
+	return found
+}
+
+// Getter nil safe getter chain of the parameter, ex: GetOrder().GetId()
+func (p *Parameter) Getter() string {
+	parts := strings.Split(p.FullParameter, ".")
+	for idx := range parts {
+		parts[idx] = "Get" + parts[idx] + "()"
+	}
+	return strings.Join(parts, ".")
 }
 
 func parseParameters(
@@ -38,14 +420,15 @@ func parseParameters(
 	pathKeys map[string]string,
 	keypref string,
 	reqkeypref string,
-) []Parameter {
+	opts Options,
+) ([]Parameter, error) {
 	finalParams := []Parameter{}
 	for _, field := range msg.Fields {
 		kind := field.Desc.Kind()
 
 		key := keypref + field.GoName
 		isPath := false
-		requestedKey := reqkeypref + field.Desc.JSONName()
+		requestedKey := reqkeypref + opts.FieldName(field.Desc)
 		if val, ok := pathKeys[requestedKey]; ok {
 			isPath = true
 			requestedKey = val
@@ -56,52 +439,135 @@ func parseParameters(
 				field.Message.Desc.FullName() != "google.protobuf.ListValue" &&
 				field.Message.Desc.FullName() != "google.protobuf.Struct")
 
+		fieldOpts, _ := proto.GetExtension(
+			field.Desc.Options(),
+			annotations.E_Field,
+		).(*annotations.Field)
+
+		in, source := "", ""
+		sources := 0
+		if fieldOpts.GetHeader() != "" {
+			in, source = "header", fieldOpts.GetHeader()
+			sources++
+		}
+		if fieldOpts.GetCookie() != "" {
+			in, source = "cookie", fieldOpts.GetCookie()
+			sources++
+		}
+		if fieldOpts.GetContentType() {
+			in, source = "header", "Content-Type"
+			sources++
+		}
+		if fieldOpts.GetRawBody() {
+			in, source = "body", requestedKey
+			sources++
+		}
+		if fieldOpts.GetFormFile() != "" {
+			in, source = "form", fieldOpts.GetFormFile()
+			sources++
+		}
+		if sources > 1 {
+			return nil, fmt.Errorf("fields can only be bound from one source: %s", requestedKey)
+		}
+		if in != "" {
+			if keypref != "" || ismsg || field.Desc.IsList() || isPath {
+				return nil, fmt.Errorf(
+					"only top level scalar fields can be bound from headers, cookies or bodies: %s",
+					requestedKey,
+				)
+			}
+			if (in == "body" || in == "form") && kind != protoreflect.BytesKind {
+				return nil, fmt.Errorf(
+					"raw bodies and form files have to be bound to bytes fields: %s",
+					requestedKey,
+				)
+			}
+			requestedKey = source
+		}
+		if fieldOpts.GetServerPopulated() && (keypref != "" || isPath || in != "") {
+			return nil, fmt.Errorf(
+				"only top level fields not bound from the path, headers or cookies can be server populated: %s",
+				requestedKey,
+			)
+		}
+		if isPath && field.Desc.IsList() {
+			return nil, fmt.Errorf("path parameters can not be repeated: %s", requestedKey)
+		}
+
 		switch ismsg {
 		case true:
+			holding, err := parseParameters(
+				field.Message,
+				pathKeys,
+				key+".",
+				requestedKey+".",
+				opts,
+			)
+			if err != nil {
+				return nil, err
+			}
 			p := Parameter{
-				field,
-				requestedKey,
-				key,
-				field.GoName,
-				"struct",
-				field.Desc.HasOptionalKeyword(),
-				field.Desc.IsList(),
-				isPath,
-				parseParameters(field.Message, pathKeys, key+".", requestedKey+"."),
+				Field:           field,
+				RequestedKey:    requestedKey,
+				FullParameter:   key,
+				PropertyName:    field.GoName,
+				Type:            "struct",
+				IsOptional:      field.Desc.HasOptionalKeyword(),
+				IsList:          field.Desc.IsList(),
+				IsPath:          isPath,
+				Holding:         holding,
+				ResourceType:    fieldOpts.GetResourceType(),
+				ServerPopulated: fieldOpts.GetServerPopulated(),
 			}
 			finalParams = append(finalParams, p)
 		default:
 			_, rawType, _ := getGolangType(field)
 			p := Parameter{
-				field,
-				requestedKey,
-				key,
-				field.GoName,
-				rawType,
-				field.Desc.HasOptionalKeyword(),
-				field.Desc.IsList(),
-				isPath,
-				[]Parameter{},
+				Field:           field,
+				RequestedKey:    requestedKey,
+				FullParameter:   key,
+				PropertyName:    field.GoName,
+				Type:            rawType,
+				IsOptional:      field.Desc.HasOptionalKeyword(),
+				IsList:          field.Desc.IsList(),
+				IsPath:          isPath,
+				Holding:         []Parameter{},
+				ResourceType:    fieldOpts.GetResourceType(),
+				In:              in,
+				ServerPopulated: fieldOpts.GetServerPopulated(),
 			}
 			finalParams = append(finalParams, p)
 		}
 	}
 
-	return finalParams
+	return finalParams, nil
 }
 
-func countPathParameters(
+// unmatchedPathKeys path keys not bound to any of the parameters, sorted
+func unmatchedPathKeys(
 	prms []Parameter,
-) int {
-	count := 0
-	for idx := range prms {
-		if len(prms[idx].Holding) != 0 {
-			count += countPathParameters(prms[idx].Holding)
-		} else if prms[idx].IsPath {
-			count++
+	pathKeys map[string]string,
+) []string {
+	matched := map[string]struct{}{}
+	var match func(prms []Parameter)
+	match = func(prms []Parameter) {
+		for idx := range prms {
+			if len(prms[idx].Holding) != 0 {
+				match(prms[idx].Holding)
+			} else if prms[idx].IsPath {
+				matched[prms[idx].RequestedKey] = struct{}{}
+			}
+		}
+	}
+	match(prms)
+	unmatched := []string{}
+	for _, key := range pathKeys {
+		if _, ok := matched[key]; !ok {
+			unmatched = append(unmatched, "{"+key+"}")
 		}
 	}
-	return count
+	sort.Strings(unmatched)
+	return unmatched
 }
 
 func getGolangType(f *protogen.Field) (fullType, rawType string, notAType bool) {
@@ -200,5 +666,56 @@ type Parameter struct {
 	IsList        bool
 	IsPath        bool
 	Holding       []Parameter
+	ResourceType  string
+	// In is "header" or "cookie" for fields bound from a request header or
+	// cookie, RequestedKey then holds the header or cookie name. It is "body"
+	// for fields holding the raw body and "form" for fields bound from a
+	// multipart file part named RequestedKey
+	In string
+	// ServerPopulated is set for fields filled in by the server rather than
+	// the client
+	ServerPopulated bool
+	// Defaulted is set for fields left at their zero value when they are not
+	// sent, ex. the page_size and page_token of paginated queries
+	Defaulted bool
 	// resolve Pointer to Input
 }
+
+// Options plugin parameters
+type Options struct {
+	// EnumCaseInsensitive accepts enum names regardless of case in query and
+	// path parameters
+	EnumCaseInsensitive bool
+	// RejectUnspecifiedEnums rejects *_UNSPECIFIED zero values in query and
+	// path parameters
+	RejectUnspecifiedEnums bool
+	// RequireRoles fails generation for rpcs that declare no roles and are not
+	// marked anonymous
+	RequireRoles bool
+	// RegoPolicy generates an OPA policy from the roles and features
+	RegoPolicy bool
+	// CasbinPolicy generates a casbin model and policy from the roles
+	CasbinPolicy bool
+	// JSONProtoNames uses proto field names instead of lower camel case json
+	// names in responses, query parameters and the OpenAPI output
+	JSONProtoNames bool
+	// JSON marshaling of responses, rpcs can override it
+	JSON JSONOptions
+	// OTel instruments the controllers with OpenTelemetry spans and metrics
+	OTel bool
+}
+
+// FieldName name of the field in json and query parameters
+func (o Options) FieldName(field protoreflect.FieldDescriptor) string {
+	if o.JSONProtoNames {
+		return string(field.Name())
+	}
+	return field.JSONName()
+}
+
+// JSONOptions json marshaling options of responses
+type JSONOptions struct {
+	EnumNumbers     bool
+	EmitUnpopulated bool
+	Indent          string
+}
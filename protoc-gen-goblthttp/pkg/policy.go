@@ -0,0 +1,125 @@
+package pkg
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+//go:embed policy.rego
+var regoPolicy string
+
+//go:embed policy.conf
+var casbinModel string
+
+type policyRoute struct {
+	Service    string   `json:"service"`
+	FullMethod string   `json:"full_method"`
+	HTTPMethod string   `json:"http_method"`
+	Path       string   `json:"path"`
+	Roles      []string `json:"roles"`
+	Features   []string `json:"features"`
+	Anonymous  bool     `json:"anonymous"`
+}
+
+// GenerateRegoPolicy generates an OPA policy module along with the data
+// document holding the routes it authorizes against
+func GenerateRegoPolicy(
+	srvs []Server,
+	g *protogen.GeneratedFile,
+	gdata *protogen.GeneratedFile,
+	file *protogen.File,
+) error {
+	// every file gets a module of its own, files sharing a proto package
+	// would otherwise both define its allow rule and routes
+	pkgPath := []string{}
+	if file.Desc.Package() != "" {
+		pkgPath = strings.Split(string(file.Desc.Package()), ".")
+	}
+	pkgPath = append(
+		pkgPath,
+		"authz",
+		strings.TrimPrefix(file.GoDescriptorIdent.GoName, "File_"),
+	)
+
+	g.P("# Code generated by protoc-gen-gohttp. DO NOT EDIT.")
+	g.P("# source: ", file.Desc.Path())
+	g.P()
+	g.P(strings.TrimSuffix(
+		strings.ReplaceAll(regoPolicy, "{{PACKAGE}}", strings.Join(pkgPath, ".")),
+		"\n",
+	))
+
+	routes := []policyRoute{}
+	for _, srv := range srvs {
+		for _, rpc := range srv.Paths {
+			routes = append(routes, policyRoute{
+				Service:    string(srv.Service.Desc.FullName()),
+				FullMethod: rpc.FullMethod(),
+				HTTPMethod: rpc.HTTPMethod,
+				Path:       rpc.OpenAPIPath,
+				Roles:      append([]string{}, rpc.Roles...),
+				Features:   append([]string{}, rpc.Features...),
+				Anonymous:  rpc.Anonymous,
+			})
+		}
+	}
+
+	var data interface{} = map[string]interface{}{"routes": routes}
+	for idx := len(pkgPath) - 1; idx >= 0; idx-- {
+		data = map[string]interface{}{pkgPath[idx]: data}
+	}
+	jsonraw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	gdata.P(string(jsonraw))
+	return nil
+}
+
+// CasbinAuthenticatedRole role granted the routes that declare no roles but
+// are not anonymous, applications assign it to every authenticated subject
+const CasbinAuthenticatedRole = "authenticated"
+
+// GenerateCasbinPolicy generates a casbin RBAC model and the policies
+// granting each role access to the routes declaring it, routes without roles
+// are granted to the CasbinAuthenticatedRole and only anonymous routes are
+// granted to *. Features are not gated by the casbin output, they are left to
+// the FeatureGate.
+func GenerateCasbinPolicy(
+	srvs []Server,
+	gmodel *protogen.GeneratedFile,
+	gpolicy *protogen.GeneratedFile,
+	file *protogen.File,
+) error {
+	gmodel.P("# Code generated by protoc-gen-gohttp. DO NOT EDIT.")
+	gmodel.P("# source: ", file.Desc.Path())
+	gmodel.P("# Features are not gated by this model, rpcs declaring features have to")
+	gmodel.P("# be gated by the FeatureGate as well. Rpcs without roles are granted to")
+	gmodel.P("# the ", CasbinAuthenticatedRole, " role, assign it to every authenticated subject,")
+	gmodel.P("# ex. g, alice, ", CasbinAuthenticatedRole, ".")
+	gmodel.P()
+	gmodel.P(strings.TrimSuffix(casbinModel, "\n"))
+
+	for _, srv := range srvs {
+		for _, rpc := range srv.Paths {
+			if rpc.Anonymous {
+				gpolicy.P("p, *, ", rpc.OpenAPIPath, ", ", rpc.HTTPMethod)
+				continue
+			}
+			if len(rpc.Roles) == 0 {
+				gpolicy.P(
+					"p, ", CasbinAuthenticatedRole, ", ",
+					rpc.OpenAPIPath, ", ", rpc.HTTPMethod,
+				)
+				continue
+			}
+			for _, role := range rpc.Roles {
+				gpolicy.P("p, ", role, ", ", rpc.OpenAPIPath, ", ", rpc.HTTPMethod)
+			}
+		}
+	}
+	return nil
+}
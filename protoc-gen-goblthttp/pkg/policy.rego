@@ -0,0 +1,79 @@
+package {{PACKAGE}}
+
+import rego.v1
+
+# Decides if a caller may invoke an rpc, expects the input to carry the
+# request method and path (or the grpc full_method) along with the subject
+# the caller authenticated as, their roles and the features enabled for them,
+# ex: {"method": "GET", "path": "/orders/1", "subject": "alice",
+# "roles": ["reader"], "features": []}
+
+default allow := false
+
+allow if {
+	some route in data.{{PACKAGE}}.routes
+	route_matches(route)
+	route_allowed(route)
+}
+
+route_matches(route) if {
+	route.full_method == input.full_method
+}
+
+route_matches(route) if {
+	route.http_method == input.method
+	path_matches(route.path, input.path)
+}
+
+route_allowed(route) if {
+	route.anonymous
+}
+
+route_allowed(route) if {
+	not route.anonymous
+	authenticated
+	roles_satisfied(route.roles)
+	features_satisfied(route.features)
+}
+
+# only anonymous rpcs can be called without a subject
+authenticated if {
+	is_string(input.subject)
+	input.subject != ""
+}
+
+# any one of the declared roles grants access, rpcs without roles only
+# require the caller to be authenticated
+roles_satisfied(roles) if {
+	count(roles) == 0
+}
+
+roles_satisfied(roles) if {
+	some role in roles
+	role in input.roles
+}
+
+# every declared feature has to be enabled
+features_satisfied(features) if {
+	every feature in features {
+		feature in input.features
+	}
+}
+
+path_matches(template, path) if {
+	template_segments := split(trim_right(template, "/"), "/")
+	path_segments := split(trim_right(path, "/"), "/")
+	count(template_segments) == count(path_segments)
+	every idx, segment in template_segments {
+		segment_matches(segment, path_segments[idx])
+	}
+}
+
+segment_matches(segment, _) if {
+	startswith(segment, "{")
+	endswith(segment, "}")
+}
+
+segment_matches(segment, value) if {
+	segment == value
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	// "google.golang.org/genproto/googleapis/api/annotations"
 	// "google.golang.org/genproto/googleapis/api/serviceconfig"
@@ -10,6 +14,7 @@ import (
 	"github.com/BetaLixT/golang-tooling/protoc-gen-goblthttp/pkg"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/descriptorpb"
 	// "google.golang.org/protobuf/proto"
 	// "google.golang.org/protobuf/runtime/protoimpl"
@@ -19,10 +24,75 @@ import (
 )
 
 func main() {
-	protogen.Options{}.Run(func(p *protogen.Plugin) error {
+	var flags flag.FlagSet
+	opts := pkg.Options{}
+	flags.BoolVar(
+		&opts.EnumCaseInsensitive,
+		"enum_case_insensitive",
+		false,
+		"accept enum names regardless of case in query and path parameters",
+	)
+	flags.BoolVar(
+		&opts.RejectUnspecifiedEnums,
+		"reject_unspecified_enums",
+		false,
+		"reject *_UNSPECIFIED enum values in query and path parameters",
+	)
+	flags.BoolVar(
+		&opts.RequireRoles,
+		"require_roles",
+		false,
+		"fail on rpcs that declare no roles and are not marked anonymous",
+	)
+	flags.BoolVar(
+		&opts.RegoPolicy,
+		"rego",
+		false,
+		"generate an OPA rego policy and data document from the roles and features",
+	)
+	flags.BoolVar(
+		&opts.CasbinPolicy,
+		"casbin",
+		false,
+		"generate a casbin model and policy from the roles",
+	)
+	flags.BoolVar(
+		&opts.JSONProtoNames,
+		"json_proto_names",
+		false,
+		"use proto field names instead of lower camel case json names",
+	)
+	flags.BoolVar(
+		&opts.JSON.EnumNumbers,
+		"json_enum_numbers",
+		false,
+		"write enums in responses as numbers instead of names",
+	)
+	flags.BoolVar(
+		&opts.JSON.EmitUnpopulated,
+		"json_emit_unpopulated",
+		true,
+		"write fields holding their zero value in responses",
+	)
+	flags.StringVar(
+		&opts.JSON.Indent,
+		"json_indent",
+		"",
+		"indent responses with the whitespace given",
+	)
+	flags.BoolVar(
+		&opts.OTel,
+		"otel",
+		false,
+		"instrument the controllers with OpenTelemetry spans and metrics",
+	)
+
+	protogen.Options{
+		ParamFunc: flags.Set,
+	}.Run(func(p *protogen.Plugin) error {
 		for _, f := range p.Files {
 			if f.Generate {
-				if err := GenerateFile(p, f); err != nil {
+				if err := GenerateFile(p, f, opts); err != nil {
 					return err
 				}
 			}
@@ -36,6 +106,7 @@ func main() {
 func GenerateFile(
 	plugin *protogen.Plugin,
 	file *protogen.File,
+	opts pkg.Options,
 ) error {
 	isGenerated := false
 	for _, srv := range file.Services {
@@ -50,6 +121,7 @@ func GenerateFile(
 	if !isGenerated {
 		return nil
 	}
+	opts = parseFileJSONOptions(file, opts)
 	plugin.SupportedFeatures = 1
 	protojsonPackage := protogen.GoImportPath("google.golang.org/protobuf/encoding/protojson")
 	gofilename := file.GeneratedFilenamePrefix + ".http.go"
@@ -59,12 +131,20 @@ func GenerateFile(
 	gohttp.P("// source: ", file.Desc.Path())
 	gohttp.P()
 	gohttp.P("package ", file.GoPackageName)
+	gohttp.P("// InternalContextKey gin key the default context factory reads the context")
+	gohttp.P("// rpcs are invoked with from")
+	gohttp.P("//")
+	gohttp.P("// Deprecated: use WithContextFactory")
 	gohttp.P("const InternalContextKey = \"inCxt\"")
-	gohttp.P(
-		"var protomarsh = ",
-		protojsonPackage.Ident("MarshalOptions"),
-		"{EmitUnpopulated: true}",
-	)
+	if strings.Trim(opts.JSON.Indent, " \t") != "" {
+		return fmt.Errorf("json indent has to be spaces or tabs")
+	}
+	gohttp.P("var protomarsh = ", protojsonPackage.Ident("MarshalOptions"), "{")
+	gohttp.P("UseProtoNames: ", opts.JSONProtoNames, ",")
+	gohttp.P("UseEnumNumbers: ", opts.JSON.EnumNumbers, ",")
+	gohttp.P("EmitUnpopulated: ", opts.JSON.EmitUnpopulated, ",")
+	gohttp.P("Indent: ", strconv.Quote(opts.JSON.Indent), ",")
+	gohttp.P("}")
 
 	yamlfilename := file.GeneratedFilenamePrefix + ".http.yaml"
 	openapi := plugin.NewGeneratedFile(yamlfilename, file.GoImportPath)
@@ -74,11 +154,16 @@ func GenerateFile(
 
 	jsonfilename := file.GeneratedFilenamePrefix + ".http.json"
 	openapijson := plugin.NewGeneratedFile(jsonfilename, file.GoImportPath)
+	docshtml := plugin.NewGeneratedFile(
+		file.GeneratedFilenamePrefix+".http.html",
+		file.GoImportPath,
+	)
 	permsjson := plugin.NewGeneratedFile(
 		file.GeneratedFilenamePrefix+".perms.json",
 		file.GoImportPath,
 	)
 
+	var err error
 	cnqs := map[string]struct{}{}
 	srvs := []pkg.Server{}
 	allPaths := map[string]struct{}{}
@@ -115,6 +200,12 @@ func GenerateFile(
 			if !ok {
 				return fmt.Errorf("documentation missing from rpc")
 			}
+			if opts.RequireRoles && len(doc.Roles) == 0 && !doc.Anonymous {
+				return fmt.Errorf(
+					"rpc %s declares no roles and is not marked anonymous",
+					rpc.Desc.FullName(),
+				)
+			}
 			method := "POST"
 
 			if doc.Rules != nil {
@@ -139,7 +230,10 @@ func GenerateFile(
 				}
 			}
 
-			fmtPath, pattern, pathKeys := parsePath(path)
+			fmtPath, pattern, pathKeys, err := parsePath(path)
+			if err != nil {
+				return fmt.Errorf("rpc %s: %w", rpc.Desc.FullName(), err)
+			}
 			reg := method + ":" + pattern
 			if _, ok = allPaths[reg]; ok {
 				return fmt.Errorf("duplicate path found")
@@ -147,33 +241,132 @@ func GenerateFile(
 			allPaths[reg] = struct{}{}
 
 			pth := pkg.APIPath{
-				rpc,
-				doc.Tags,
-				doc.Roles,
-				doc.Features,
-				doc.Summary,
-				doc.Description,
-				fmtPath,
-				path,
-				method,
-				[]pkg.Parameter{},
-			}
-			pth.BuildParameters(pathKeys)
+				Method:      rpc,
+				Tags:        doc.Tags,
+				Roles:       doc.Roles,
+				Features:    doc.Features,
+				Description: doc.Description,
+				Summary:     doc.Summary,
+				GoPath:      fmtPath,
+				OpenAPIPath: path,
+				HTTPMethod:  method,
+				Parameters:  []pkg.Parameter{},
+				Anonymous:   doc.Anonymous,
+				Idempotent:  doc.Idempotent,
+			}
+			err = pth.BuildParameters(pathKeys, opts)
+			if err != nil {
+				return fmt.Errorf("rpc %s: %w", rpc.Desc.FullName(), err)
+			}
+			if pth.Idempotent && !pth.IsCommand() {
+				return fmt.Errorf(
+					"rpc %s is marked idempotent but is not a command",
+					rpc.Desc.FullName(),
+				)
+			}
+
+			pth.Deprecation, err = parseDeprecation(options, doc)
+			if err != nil {
+				return err
+			}
+
+			pth.RateLimit, err = parseRateLimit(doc)
+			if err != nil {
+				return fmt.Errorf("rpc %s: %w", rpc.Desc.FullName(), err)
+			}
+
+			pth.Cache, err = parseCache(&pth, doc)
+			if err != nil {
+				return fmt.Errorf("rpc %s: %w", rpc.Desc.FullName(), err)
+			}
+
+			pth.Concurrency, err = parseConcurrency(&pth, doc)
+			if err != nil {
+				return fmt.Errorf("rpc %s: %w", rpc.Desc.FullName(), err)
+			}
+
+			pth.Pagination, err = parsePagination(&pth, doc)
+			if err != nil {
+				return fmt.Errorf("rpc %s: %w", rpc.Desc.FullName(), err)
+			}
+
+			pth.JSON, err = parseJSONOptions(doc, opts)
+			if err != nil {
+				return fmt.Errorf("rpc %s: %w", rpc.Desc.FullName(), err)
+			}
+
+			pth.Timeout, err = parseTimeout(doc)
+			if err != nil {
+				return fmt.Errorf("rpc %s: %w", rpc.Desc.FullName(), err)
+			}
+
+			pth.Body, pth.RawResponse, err = parseBody(file, &pth, doc)
+			if err != nil {
+				return fmt.Errorf("rpc %s: %w", rpc.Desc.FullName(), err)
+			}
+
+			if method == "PATCH" {
+				for _, field := range rpc.Input.Fields {
+					if field.Desc.Name() == "update_mask" && field.Message != nil &&
+						field.Message.Desc.FullName() == "google.protobuf.FieldMask" {
+						pth.UpdateMask = field
+					}
+				}
+			}
 
 			pths = append(pths, pth)
 
 		}
+		cors, err := parseCORS(file, srv)
+		if err != nil {
+			return fmt.Errorf("service %s: %w", srv.Desc.FullName(), err)
+		}
 		srvs = append(srvs, pkg.Server{
 			Service: srv,
 			Paths:   pths,
+			CORS:    cors,
 		})
 	}
 
-	err := pkg.GenerateHTTPServers(srvs, gohttp, file)
+	err = pkg.GenerateHTTPServers(srvs, gohttp, file, opts)
 	if err != nil {
 		return err
 	}
 
+	err = pkg.GeneratePermissionRegistry(srvs, gohttp)
+	if err != nil {
+		return err
+	}
+
+	err = pkg.GenerateHTTPDocs(srvs, gohttp, docshtml, file)
+	if err != nil {
+		return err
+	}
+
+	if opts.RegoPolicy {
+		err = pkg.GenerateRegoPolicy(
+			srvs,
+			plugin.NewGeneratedFile(file.GeneratedFilenamePrefix+".authz.rego", file.GoImportPath),
+			plugin.NewGeneratedFile(file.GeneratedFilenamePrefix+".authz.json", file.GoImportPath),
+			file,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.CasbinPolicy {
+		err = pkg.GenerateCasbinPolicy(
+			srvs,
+			plugin.NewGeneratedFile(file.GeneratedFilenamePrefix+".casbin.conf", file.GoImportPath),
+			plugin.NewGeneratedFile(file.GeneratedFilenamePrefix+".casbin.csv", file.GoImportPath),
+			file,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = pkg.GeneratePermisionMaps(srvs, permsjson)
 	if err != nil {
 		return err
@@ -191,16 +384,16 @@ func GenerateFile(
 			}
 		}
 		if len(pths) != 0 {
-			srvs2 = append(srvs2, pkg.Server{srvs[idx].Service, pths})
+			srvs2 = append(srvs2, pkg.Server{Service: srvs[idx].Service, Paths: pths})
 		}
 	}
 
-	return pkg.GenerateOpenAPI(srvs, openapi, openapijson, file)
+	return pkg.GenerateOpenAPI(srvs, openapi, openapijson, file, opts)
 }
 
 func parsePath(
 	path string,
-) (formattedPath string, matchedPattern string, pathKeys map[string]string) {
+) (formattedPath string, matchedPattern string, pathKeys map[string]string, err error) {
 	segments := strings.Split(path, "/")
 	patternSegments := make([]string, len(segments))
 	pathKeys = map[string]string{}
@@ -213,8 +406,9 @@ func parsePath(
 			for ridx := 1; ridx < len(segments[idx])-1; ridx++ {
 
 				if segments[idx][ridx] == '=' {
-					panic(
-						"path templating not currently supported, ex: {var} is supported not {var=*}",
+					return "", "", nil, fmt.Errorf(
+						"path templating not currently supported, ex: {var} is supported not {var=*}: %s",
+						path,
 					)
 				}
 				variable[ridx-1] = rune(segments[idx][ridx])
@@ -227,5 +421,394 @@ func parsePath(
 			patternSegments[idx] = segments[idx]
 		}
 	}
-	return strings.Join(segments, "/"), strings.Join(patternSegments, "/"), pathKeys
+	return strings.Join(segments, "/"), strings.Join(patternSegments, "/"), pathKeys, nil
+}
+
+func parseDeprecation(
+	options *descriptorpb.MethodOptions,
+	doc *annotations.Documentation,
+) (pkg.Deprecation, error) {
+	dep := pkg.Deprecation{
+		Deprecated: options.GetDeprecated() || doc.Deprecation != nil,
+		Link:       doc.GetDeprecation().GetLink(),
+	}
+
+	var err error
+	if since := doc.GetDeprecation().GetSince(); since != "" {
+		dep.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			return dep, fmt.Errorf("invalid deprecation since timestamp %s", since)
+		}
+	}
+	if sunset := doc.GetDeprecation().GetSunset(); sunset != "" {
+		dep.Sunset, err = time.Parse(time.RFC3339, sunset)
+		if err != nil {
+			return dep, fmt.Errorf("invalid deprecation sunset timestamp %s", sunset)
+		}
+	}
+	return dep, nil
+}
+
+func parseCORS(file *protogen.File, srv *protogen.Service) (*pkg.CORS, error) {
+	cors, _ := proto.GetExtension(
+		srv.Desc.Options(),
+		annotations.E_ServiceCors,
+	).(*annotations.CORS)
+	if cors == nil {
+		cors, _ = proto.GetExtension(
+			file.Desc.Options(),
+			annotations.E_FileCors,
+		).(*annotations.CORS)
+	}
+	if cors == nil {
+		return nil, nil
+	}
+	if len(cors.AllowedOrigins) == 0 {
+		return nil, fmt.Errorf("cors requires allowed_origins")
+	}
+	for _, origin := range cors.AllowedOrigins {
+		if origin == "*" && cors.AllowCredentials {
+			return nil, fmt.Errorf("cors credentials can not be allowed for any origin")
+		}
+	}
+	return &pkg.CORS{
+		AllowedOrigins:   cors.AllowedOrigins,
+		AllowedHeaders:   cors.AllowedHeaders,
+		ExposedHeaders:   cors.ExposedHeaders,
+		AllowCredentials: cors.AllowCredentials,
+		MaxAgeSeconds:    cors.MaxAgeSeconds,
+	}, nil
+}
+
+func parseBody(
+	file *protogen.File,
+	pth *pkg.APIPath,
+	doc *annotations.Documentation,
+) (*pkg.Body, *pkg.RawResponse, error) {
+	opts := doc.GetBody()
+	body := &pkg.Body{
+		Stream:       opts.GetStream(),
+		ContentTypes: opts.GetContentTypes(),
+		MaxBytes:     opts.GetMaxBytes(),
+	}
+	if body.MaxBytes == 0 {
+		body.MaxBytes = 32 << 20
+	}
+	var files []*protogen.Field
+	for _, prm := range pth.Parameters {
+		switch prm.In {
+		case "body":
+			if body.Field != nil {
+				return nil, nil, fmt.Errorf("multiple raw body fields in the input")
+			}
+			body.Field = prm.Field
+		case "form":
+			body.Multipart = true
+			files = append(files, prm.Field)
+		}
+	}
+	if body.Field != nil && body.Multipart {
+		return nil, nil, fmt.Errorf("inputs can not have both raw body and form file fields")
+	}
+	if body.Stream && body.Multipart {
+		if len(files) != 1 {
+			return nil, nil, fmt.Errorf(
+				"streamed multipart bodies can only have one form file field",
+			)
+		}
+		body.Field = files[0]
+	}
+	if body.Stream {
+		if body.Field == nil {
+			return nil, nil, fmt.Errorf(
+				"streamed bodies have to be bound to a raw body or form file field",
+			)
+		}
+		if pth.Method.Input.GoIdent.GoImportPath != file.GoImportPath {
+			return nil, nil, fmt.Errorf(
+				"inputs with streamed fields have to be in the package of the service",
+			)
+		}
+		reader := body.Field.GoName + "Reader"
+		for _, field := range pth.Method.Input.Fields {
+			if field.GoName == reader {
+				return nil, nil, fmt.Errorf(
+					"field %s collides with the reader of the streamed field",
+					field.Desc.Name(),
+				)
+			}
+		}
+	}
+	if body.Field == nil && !body.Multipart {
+		body = nil
+	} else if pth.HTTPMethod == "GET" || pth.HTTPMethod == "DELETE" {
+		return nil, nil, fmt.Errorf("raw bodies require a method with a body")
+	}
+
+	res := &pkg.RawResponse{
+		ContentType: opts.GetResponseContentType(),
+		Inline:      opts.GetInline(),
+	}
+	if res.ContentType == "" {
+		res.ContentType = "application/octet-stream"
+	}
+	for _, field := range pth.Method.Output.Fields {
+		fieldOpts, _ := proto.GetExtension(
+			field.Desc.Options(),
+			annotations.E_Field,
+		).(*annotations.Field)
+		kind := field.Desc.Kind()
+		switch {
+		case field.Desc.IsList():
+			if fieldOpts.GetRawBody() || fieldOpts.GetContentType() || fieldOpts.GetFilename() {
+				return nil, nil, fmt.Errorf(
+					"raw response field %s can not be repeated",
+					field.Desc.Name(),
+				)
+			}
+		case fieldOpts.GetRawBody():
+			if kind != protoreflect.BytesKind || res.Field != nil {
+				return nil, nil, fmt.Errorf("raw response body has to be a single bytes field")
+			}
+			res.Field = field
+		case fieldOpts.GetContentType():
+			if kind != protoreflect.StringKind || res.ContentTypeField != nil {
+				return nil, nil, fmt.Errorf(
+					"raw response content type has to be a single string field",
+				)
+			}
+			res.ContentTypeField = field
+		case fieldOpts.GetFilename():
+			if kind != protoreflect.StringKind || res.FilenameField != nil {
+				return nil, nil, fmt.Errorf(
+					"raw response file name has to be a single string field",
+				)
+			}
+			res.FilenameField = field
+		}
+	}
+	if res.Field == nil {
+		if res.ContentTypeField != nil || res.FilenameField != nil {
+			return nil, nil, fmt.Errorf(
+				"content type and file name fields require a raw_body field",
+			)
+		}
+		res = nil
+	}
+
+	if pth.Idempotent && (body != nil || res != nil) {
+		return nil, nil, fmt.Errorf("idempotent rpcs can not have raw bodies")
+	}
+	return body, res, nil
+}
+
+func parseTimeout(doc *annotations.Documentation) (*pkg.Timeout, error) {
+	to := doc.GetTimeout()
+	if to == nil {
+		return nil, nil
+	}
+	if to.Seconds == 0 && !to.FromHeaders {
+		return nil, fmt.Errorf("timeout requires seconds or from_headers")
+	}
+	return &pkg.Timeout{
+		Seconds:     to.Seconds,
+		FromHeaders: to.FromHeaders,
+	}, nil
+}
+
+func parseRateLimit(doc *annotations.Documentation) (*pkg.RateLimit, error) {
+	rl := doc.GetRateLimit()
+	if rl == nil {
+		return nil, nil
+	}
+	if rl.Requests == 0 || rl.WindowSeconds == 0 {
+		return nil, fmt.Errorf("rate limit requires requests and window_seconds")
+	}
+
+	limit := &pkg.RateLimit{
+		Requests:      rl.Requests,
+		WindowSeconds: rl.WindowSeconds,
+		Burst:         rl.Burst,
+		Header:        rl.Header,
+	}
+	if limit.Burst == 0 {
+		limit.Burst = limit.Requests
+	}
+	switch rl.Key {
+	case annotations.RateLimitKey_RATE_LIMIT_KEY_IP:
+		limit.Key = "ip"
+	case annotations.RateLimitKey_RATE_LIMIT_KEY_USER:
+		limit.Key = "user"
+	case annotations.RateLimitKey_RATE_LIMIT_KEY_HEADER:
+		if rl.Header == "" {
+			return nil, fmt.Errorf("rate limit keyed by header requires a header name")
+		}
+		limit.Key = "header"
+	default:
+		return nil, fmt.Errorf("unknown rate limit key %s", rl.Key)
+	}
+	return limit, nil
+}
+
+func parseCache(pth *pkg.APIPath, doc *annotations.Documentation) (*pkg.Cache, error) {
+	cache := doc.GetCache()
+	if cache == nil {
+		return nil, nil
+	}
+	if pth.IsCommand() {
+		return nil, fmt.Errorf("caching is only supported on queries")
+	}
+	if pth.HTTPMethod != "GET" {
+		// 304 responses and caching are only defined for GET
+		return nil, fmt.Errorf("caching is only supported on GET rpcs")
+	}
+
+	etag, err := pkg.ETagField(pth.Method.Output)
+	if err != nil {
+		return nil, err
+	}
+	return &pkg.Cache{
+		MaxAgeSeconds: cache.MaxAgeSeconds,
+		Private:       cache.Private,
+		Vary:          cache.Vary,
+		ETagField:     etag,
+	}, nil
+}
+
+func parseConcurrency(
+	pth *pkg.APIPath,
+	doc *annotations.Documentation,
+) (*pkg.Concurrency, error) {
+	if !pth.IsCommand() {
+		if doc.RequireIfMatch {
+			return nil, fmt.Errorf("If-Match is only supported on commands")
+		}
+		return nil, nil
+	}
+
+	field, err := pkg.ETagField(pth.Method.Input)
+	if err != nil {
+		return nil, err
+	}
+	if field == nil {
+		if doc.RequireIfMatch {
+			return nil, fmt.Errorf("requiring If-Match needs a field marked as the etag")
+		}
+		return nil, nil
+	}
+	resField, err := pkg.ETagField(pth.Method.Output)
+	if err != nil {
+		return nil, err
+	}
+	return &pkg.Concurrency{
+		Field:         field,
+		ResponseField: resField,
+		Required:      doc.RequireIfMatch,
+	}, nil
+}
+
+func parsePagination(
+	pth *pkg.APIPath,
+	doc *annotations.Documentation,
+) (*pkg.Pagination, error) {
+	field := func(msg *protogen.Message, name string, kind protoreflect.Kind) *protogen.Field {
+		for _, f := range msg.Fields {
+			if string(f.Desc.Name()) == name && f.Desc.Kind() == kind &&
+				!f.Desc.IsList() && !f.Desc.HasOptionalKeyword() {
+				return f
+			}
+		}
+		return nil
+	}
+
+	pagination := &pkg.Pagination{
+		PageSize:      field(pth.Method.Input, "page_size", protoreflect.Int32Kind),
+		PageToken:     field(pth.Method.Input, "page_token", protoreflect.StringKind),
+		NextPageToken: field(pth.Method.Output, "next_page_token", protoreflect.StringKind),
+		MaxPageSize:   doc.GetPagination().GetMaxPageSize(),
+	}
+	if pth.IsCommand() || pagination.PageSize == nil || pagination.PageToken == nil ||
+		pagination.NextPageToken == nil {
+		if doc.Pagination != nil {
+			return nil, fmt.Errorf(
+				"pagination requires a query with page_size and page_token inputs and a next_page_token output",
+			)
+		}
+		return nil, nil
+	}
+	if pagination.MaxPageSize == 0 {
+		pagination.MaxPageSize = 1000
+	}
+	// page_size is an int32, larger limits could never be reached and do not
+	// fit the generated check
+	if pagination.MaxPageSize > math.MaxInt32 {
+		return nil, fmt.Errorf(
+			"pagination max_page_size %d exceeds the int32 page_size field",
+			pagination.MaxPageSize,
+		)
+	}
+	// the first page is requested without a token and with the default size
+	for idx := range pth.Parameters {
+		prm := &pth.Parameters[idx]
+		if prm.Field == pagination.PageSize || prm.Field == pagination.PageToken {
+			prm.Defaulted = true
+		}
+	}
+	return pagination, nil
+}
+
+// parseFileJSONOptions applies the json options of a file over the plugin
+// parameters
+func parseFileJSONOptions(file *protogen.File, opts pkg.Options) pkg.Options {
+	overrides, _ := proto.GetExtension(
+		file.Desc.Options(),
+		annotations.E_FileJson,
+	).(*annotations.JSONOptions)
+	if overrides == nil {
+		return opts
+	}
+	if overrides.UseProtoNames != nil {
+		opts.JSONProtoNames = overrides.GetUseProtoNames()
+	}
+	if overrides.UseEnumNumbers != nil {
+		opts.JSON.EnumNumbers = overrides.GetUseEnumNumbers()
+	}
+	if overrides.EmitUnpopulated != nil {
+		opts.JSON.EmitUnpopulated = overrides.GetEmitUnpopulated()
+	}
+	if overrides.Indent != nil {
+		opts.JSON.Indent = overrides.GetIndent()
+	}
+	return opts
+}
+
+func parseJSONOptions(
+	doc *annotations.Documentation,
+	opts pkg.Options,
+) (*pkg.JSONOptions, error) {
+	overrides := doc.GetJson()
+	if overrides == nil {
+		return nil, nil
+	}
+	if overrides.UseProtoNames != nil {
+		return nil, fmt.Errorf("json use_proto_names is only supported on files")
+	}
+
+	json := opts.JSON
+	if overrides.UseEnumNumbers != nil {
+		json.EnumNumbers = overrides.GetUseEnumNumbers()
+	}
+	if overrides.EmitUnpopulated != nil {
+		json.EmitUnpopulated = overrides.GetEmitUnpopulated()
+	}
+	if overrides.Indent != nil {
+		json.Indent = overrides.GetIndent()
+		if strings.Trim(json.Indent, " \t") != "" {
+			return nil, fmt.Errorf("json indent has to be spaces or tabs")
+		}
+	}
+	if json == opts.JSON {
+		return nil, nil
+	}
+	return &json, nil
 }
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BetaLixT/golang-tooling/protoc-gen-goblthttp/custom/annotations"
+	"github.com/BetaLixT/golang-tooling/protoc-gen-goblthttp/pkg"
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+	"gopkg.in/yaml.v3"
+
+	// dependencies of the generated servers and their tests, required by this
+	// module so the generated servers test resolves them from the module cache
+	_ "github.com/gin-gonic/gin"
+	_ "go.opentelemetry.io/otel"
+	_ "go.opentelemetry.io/otel/sdk/metric"
+	_ "go.opentelemetry.io/otel/sdk/trace"
+	_ "google.golang.org/grpc"
+)
+
+const (
+	testModule      = "example.com/blthttptest"
+	annotationsPath = "github.com/BetaLixT/golang-tooling/protoc-gen-goblthttp/custom/annotations"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// generatedServers packages generated from testdata/orders.proto, each tested
+// with the tests in testdata/<pkg>
+var generatedServers = []struct {
+	pkg  string
+	opts pkg.Options
+}{
+	{
+		pkg:  "orders",
+		opts: pkg.Options{JSON: pkg.JSONOptions{EmitUnpopulated: true}},
+	},
+	{
+		pkg:  "strictorders",
+		opts: pkg.Options{EnumCaseInsensitive: true, RejectUnspecifiedEnums: true},
+	},
+	{
+		pkg:  "snakeorders",
+		opts: pkg.Options{JSONProtoNames: true},
+	},
+	{
+		pkg:  "tracedorders",
+		opts: pkg.Options{OTel: true},
+	},
+}
+
+// TestGeneratedServers generates the servers of testdata/orders.proto and runs
+// their tests in a temporary module. The module requires what this one does,
+// with github.com/betalixt/gorr replaced by testdata/gorr, and is built from
+// the module cache without network access
+func TestGeneratedServers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the generated servers")
+	}
+	dir := t.TempDir()
+	for _, srv := range generatedServers {
+		generateTestServer(t, dir, srv.pkg, srv.opts)
+	}
+	writeTestModule(t, dir)
+
+	if out, err := goCommand(dir, "vet", "./..."); err != nil {
+		t.Fatalf("vet: %s", out)
+	}
+	if out, err := goCommand(dir, "test", "./..."); err != nil {
+		t.Fatalf("test: %s", out)
+	}
+}
+
+// generateTestServer writes the servers generated from testdata/orders.proto
+// into the package of the name, along with its tests
+func generateTestServer(t *testing.T, dir string, name string, opts pkg.Options) {
+	t.Helper()
+	for _, f := range generateFixtures(t, opts, name, "orders.proto") {
+		if strings.HasSuffix(f.GetName(), ".http.yaml") {
+			doc := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(f.GetContent()), &doc); err != nil {
+				t.Fatalf("%s: %v", f.GetName(), err)
+			}
+		}
+		writeTestFile(t, dir, strings.TrimPrefix(f.GetName(), testModule+"/"), f.GetContent())
+	}
+	tests, err := filepath.Glob(filepath.Join("testdata", name, "*_test.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		src, err := os.ReadFile(test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeTestFile(t, dir, filepath.Join(name, filepath.Base(test)), string(src))
+	}
+}
+
+// generateFixtures runs the plugin on the files of testdata/fixtures.pb, each
+// file is generated into the package of the name under its own directory
+func generateFixtures(
+	t *testing.T,
+	opts pkg.Options,
+	name string,
+	files ...string,
+) []*pluginpb.CodeGeneratorResponse_File {
+	t.Helper()
+	res, err := runPlugin(loadFixtures(t), opts, name, files...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func loadFixtures(t *testing.T) *descriptorpb.FileDescriptorSet {
+	t.Helper()
+	raw, err := os.ReadFile("testdata/fixtures.pb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(raw, set); err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+func runPlugin(
+	set *descriptorpb.FileDescriptorSet,
+	opts pkg.Options,
+	name string,
+	files ...string,
+) ([]*pluginpb.CodeGeneratorResponse_File, error) {
+	params := []string{
+		"Mannotations.proto=" + annotationsPath + ";annotations",
+		"Mdocumentation.proto=" + annotationsPath + ";annotations",
+	}
+	for _, file := range files {
+		params = append(params, "M"+file+"="+testModule+"/"+name+";"+name)
+	}
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: files,
+		Parameter:      proto.String(strings.Join(params, ",")),
+		ProtoFile:      set.File,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range plugin.Files {
+		if !f.Generate {
+			continue
+		}
+		gengo.GenerateFile(plugin, f)
+		if err := GenerateFile(plugin, f, opts); err != nil {
+			return nil, err
+		}
+	}
+	res := plugin.Response()
+	if res.Error != nil {
+		return nil, errors.New(res.GetError())
+	}
+	return res.File, nil
+}
+
+// TestInvalidAnnotations checks generation fails on annotations of the
+// fixtures that can not be generated, each case changes the documentation of
+// an rpc or the annotations of a field of testdata/orders.proto
+func TestInvalidAnnotations(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		doc    func(doc *annotations.Documentation)
+		// field annotated instead of the method, ex: GetOrderQuery.tenant
+		field    string
+		annotate func(field *annotations.Field)
+		err      string
+	}{
+		{
+			name:   "max page size above int32",
+			method: "ListOrders",
+			doc: func(doc *annotations.Documentation) {
+				doc.Pagination.MaxPageSize = math.MaxInt32 + 1
+			},
+			err: "rpc blthttptest.Orders.ListOrders: pagination max_page_size 2147483648 exceeds",
+		},
+		{
+			name:   "path key not matching a field",
+			method: "GetOrder",
+			doc: func(doc *annotations.Documentation) {
+				doc.Rules.Pattern = &annotations.HttpRule_Get{Get: "/orders/{order_id}"}
+			},
+			err: "rpc blthttptest.Orders.GetOrder: path keys do not match any field of the input: {order_id}",
+		},
+		{
+			name:   "path templating",
+			method: "GetOrder",
+			doc: func(doc *annotations.Documentation) {
+				doc.Rules.Pattern = &annotations.HttpRule_Get{Get: "/orders/{id=*}"}
+			},
+			err: "rpc blthttptest.Orders.GetOrder: path templating not currently supported",
+		},
+		{
+			name:  "header and cookie on one field",
+			field: "GetOrderQuery.tenant",
+			annotate: func(field *annotations.Field) {
+				field.Cookie = "tenant"
+			},
+			err: "rpc blthttptest.Orders.GetOrder: fields can only be bound from one source: tenant",
+		},
+		{
+			name:  "header on a path field",
+			field: "GetOrderQuery.id",
+			annotate: func(field *annotations.Field) {
+				field.Header = "X-Id"
+			},
+			err: "rpc blthttptest.Orders.GetOrder: only top level scalar fields can be bound from headers",
+		},
+		{
+			name:  "header on a message field",
+			field: "UpdateOrderCommand.address",
+			annotate: func(field *annotations.Field) {
+				field.Header = "X-Address"
+			},
+			err: "rpc blthttptest.Orders.UpdateOrder: only top level scalar fields can be bound from headers",
+		},
+		{
+			name:  "form file on a string field",
+			field: "ImportOrdersCommand.source",
+			annotate: func(field *annotations.Field) {
+				field.FormFile = "source"
+			},
+			err: "rpc blthttptest.Orders.ImportOrders: raw bodies and form files have to be bound to bytes fields: source",
+		},
+		{
+			name:  "server populated cookie",
+			field: "GetOrderQuery.session",
+			annotate: func(field *annotations.Field) {
+				field.ServerPopulated = true
+			},
+			err: "rpc blthttptest.Orders.GetOrder: only top level fields not bound from the path, headers or cookies can be server populated: session",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := loadFixtures(t)
+			found := false
+			for _, file := range set.File {
+				for _, srv := range file.Service {
+					for _, method := range srv.Method {
+						if tt.doc == nil || method.GetName() != tt.method {
+							continue
+						}
+						doc := proto.GetExtension(
+							method.Options,
+							annotations.E_Documentation,
+						).(*annotations.Documentation)
+						tt.doc(doc)
+						proto.SetExtension(method.Options, annotations.E_Documentation, doc)
+						found = true
+					}
+				}
+				for _, msg := range file.MessageType {
+					for _, field := range msg.Field {
+						if tt.annotate == nil || msg.GetName()+"."+field.GetName() != tt.field {
+							continue
+						}
+						if field.Options == nil {
+							field.Options = &descriptorpb.FieldOptions{}
+						}
+						fieldOpts, _ := proto.GetExtension(
+							field.Options,
+							annotations.E_Field,
+						).(*annotations.Field)
+						if fieldOpts == nil {
+							fieldOpts = &annotations.Field{}
+						}
+						tt.annotate(fieldOpts)
+						proto.SetExtension(field.Options, annotations.E_Field, fieldOpts)
+						found = true
+					}
+				}
+			}
+			if !found {
+				t.Fatal("no rpc or field", tt.method, tt.field)
+			}
+			_, err := runPlugin(set, pkg.Options{}, "orders", "orders.proto")
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestPolicies compares the rego and casbin policies generated from the
+// fixtures against testdata/golden, -update rewrites them
+func TestPolicies(t *testing.T) {
+	opts := pkg.Options{RegoPolicy: true, CasbinPolicy: true}
+	for _, f := range generateFixtures(t, opts, "policies", "orders.proto", "returns.proto") {
+		name := filepath.Base(f.GetName())
+		switch filepath.Ext(name) {
+		case ".rego", ".csv", ".conf":
+		case ".json":
+			if !strings.HasSuffix(name, ".authz.json") {
+				continue
+			}
+		default:
+			continue
+		}
+		golden := filepath.Join("testdata", "golden", name)
+		if *update {
+			writeTestFile(t, filepath.Dir(golden), name, f.GetContent())
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(want) != f.GetContent() {
+			t.Errorf("%s differs from %s:\n%s", f.GetName(), golden, f.GetContent())
+		}
+	}
+}
+
+// writeTestModule writes the go.mod of the generated servers, with the
+// requirements and checksums of this module
+func writeTestModule(t *testing.T, dir string) {
+	t.Helper()
+	gomod, err := os.ReadFile("go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gosum, err := os.ReadFile("go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gorr, err := filepath.Abs(filepath.Join("testdata", "gorr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, requires, _ := strings.Cut(string(gomod), "\n")
+	writeTestFile(t, dir, "go.mod", "module "+testModule+"\n"+requires+`
+require (
+	github.com/BetaLixT/golang-tooling/protoc-gen-goblthttp v0.0.0
+	github.com/betalixt/gorr v0.0.0
+)
+
+replace (
+	github.com/BetaLixT/golang-tooling/protoc-gen-goblthttp => `+src+`
+	github.com/betalixt/gorr => `+gorr+`
+)
+`)
+	writeTestFile(t, dir, "go.sum", string(gosum))
+}
+
+func writeTestFile(t *testing.T, dir string, name string, content string) {
+	t.Helper()
+	name = filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// goCommand runs the go command in the module of the generated servers,
+// resolving modules from the module cache only
+func goCommand(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(
+		os.Environ(),
+		"GOWORK=off",
+		"GOFLAGS=-mod=mod",
+		"GOPROXY=off",
+		"GOSUMDB=off",
+		"GOTOOLCHAIN=local",
+	)
+	return cmd.CombinedOutput()
+}
@@ -0,0 +1,3 @@
+module github.com/betalixt/gorr
+
+go 1.19
@@ -0,0 +1,25 @@
+// Package gorr stand-in for the parts of github.com/betalixt/gorr the
+// generated servers use, so the generated servers test runs offline
+package gorr
+
+// ErrorCode code and message of an error
+type ErrorCode struct {
+	Code    int
+	Message string
+}
+
+// Error error responded with
+type Error struct {
+	ErrorCode
+	StatusCode  int
+	ErrorDetail string
+}
+
+// NewError creates an error responded with the status code
+func NewError(code ErrorCode, statusCode int, detail string) *Error {
+	return &Error{ErrorCode: code, StatusCode: statusCode, ErrorDetail: detail}
+}
+
+func (e *Error) Error() string {
+	return e.Message + ": " + e.ErrorDetail
+}
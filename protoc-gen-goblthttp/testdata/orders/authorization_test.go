@@ -0,0 +1,18 @@
+package orders
+
+import "testing"
+
+func TestAuthorization(t *testing.T) {
+	for _, opt := range []HTTPServerOption{WithAuthorizer(nil), WithAuthorizer(authorizer{deny: true})} {
+		r := newRouter(&orderServer{}, opt)
+		if w := do(r, "GET", "/orders/a", ""); w.Code != 403 || w.Body.String() != "ForbiddenError" {
+			t.Fatal(w.Code, w.Body.String())
+		}
+		if w := do(r, "PUT", "/orders/a", `{}`); w.Code != 403 {
+			t.Fatal(w.Code, w.Body.String())
+		}
+		if w := do(r, "GET", "/archive/status", ""); w.Code != 200 {
+			t.Fatal(w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package orders
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSGroups(t *testing.T) {
+	r := gin.New()
+	preflights := &sync.Map{}
+	opts := []HTTPServerOption{WithAuthorizer(authorizer{}), WithCORSPreflights(preflights)}
+	RegisterOrdersHTTPServer(r.Group("/api"), &orderServer{}, opts...)
+	RegisterArchiveHTTPServer(r.Group("/api"), archiveServer{}, opts...)
+	RegisterArchiveHTTPServer(r.Group("/v2"), archiveServer{}, opts...)
+	w := do(r, "OPTIONS", "/api/orders/a", "", "Origin", "https://admin.example.com")
+	if w.Code != 204 || w.Header().Get("Allow") != "OPTIONS, GET, POST, PATCH, PUT" ||
+		w.Header().Get("Access-Control-Allow-Methods") != "PUT" {
+		t.Fatal(w.Code, w.Header())
+	}
+	w = do(r, "OPTIONS", "/v2/orders/a", "", "Origin", "https://admin.example.com")
+	if w.Code != 204 || w.Header().Get("Allow") != "OPTIONS, PUT" {
+		t.Fatal(w.Code, w.Header())
+	}
+}
+
+func TestCORSPreflightsOfOtherPackages(t *testing.T) {
+	// another package registering the route first, the way its generated
+	// servers do
+	other := func(r *gin.Engine, preflights *sync.Map) {
+		chain := []gin.HandlerFunc{
+			func(ctx *gin.Context) { ctx.Header("Allow", "OPTIONS, DELETE") },
+		}
+		r.OPTIONS("/orders/:id", func(ctx *gin.Context) {
+			for _, handler := range chain {
+				handler(ctx)
+			}
+			ctx.AbortWithStatus(204)
+		})
+		preflights.Store(
+			"/orders/:id",
+			func(handler gin.HandlerFunc) { chain = append(chain, handler) },
+		)
+	}
+
+	r := gin.New()
+	preflights := &sync.Map{}
+	other(r, preflights)
+	RegisterArchiveHTTPServer(&r.RouterGroup, archiveServer{}, WithCORSPreflights(preflights))
+	w := do(r, "OPTIONS", "/orders/a", "", "Origin", "https://admin.example.com")
+	if w.Code != 204 || w.Header().Get("Allow") != "OPTIONS, DELETE, PUT" {
+		t.Fatal(w.Code, w.Header())
+	}
+
+	r = gin.New()
+	other(r, preflights)
+	defer func() {
+		if rec := recover(); !strings.Contains(fmt.Sprint(rec), "WithCORSPreflights") {
+			t.Fatal(rec)
+		}
+	}()
+	RegisterArchiveHTTPServer(&r.RouterGroup, archiveServer{})
+	t.Fatal("registered the route twice")
+}
@@ -0,0 +1,54 @@
+package orders
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDeprecationHeaders(t *testing.T) {
+	called := []string{}
+	r := newRouter(&orderServer{}, WithDeprecatedCallHook(func(_ *gin.Context, fullMethod string) {
+		called = append(called, fullMethod)
+	}))
+	w := do(r, "PUT", "/orders/a", `{}`)
+	if w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	for key, want := range map[string]string{
+		"Deprecation": "@1704067200",
+		"Sunset":      "Wed, 01 Jan 2025 00:00:00 GMT",
+		"Link":        `<https://example.com/archive>; rel="deprecation"`,
+	} {
+		if got := w.Header().Get(key); got != want {
+			t.Fatal(key, got)
+		}
+	}
+	if len(called) != 1 || called[0] != "/blthttptest.Archive/ArchiveOrder" {
+		t.Fatal(called)
+	}
+
+	w = do(r, "GET", "/orders/a", "")
+	if w.Code != 200 || w.Header().Get("Deprecation") != "" || len(called) != 1 {
+		t.Fatal(w.Code, w.Header(), called)
+	}
+}
+
+func TestDeprecationOpenAPI(t *testing.T) {
+	spec := openAPI(t, newRouter(&orderServer{}))
+	if lookup(spec, "paths", "/orders/{id}", "put", "deprecated") != true {
+		t.Fatal(lookup(spec, "paths", "/orders/{id}", "put"))
+	}
+	if lookup(spec, "paths", "/orders/{id}", "get", "deprecated") != nil {
+		t.Fatal(lookup(spec, "paths", "/orders/{id}", "get"))
+	}
+	reason := lookup(spec, "components", "schemas", "ArchiveOrderCommand", "properties", "reason")
+	if lookup(reason, "deprecated") != true {
+		t.Fatal(reason)
+	}
+	status := lookup(spec, "components", "schemas", "Order", "properties", "status")
+	if deprecated, _ := lookup(status, "x-enum-deprecated").([]interface{}); len(deprecated) != 1 ||
+		deprecated[0] != "ORDER_STATUS_LEGACY" {
+		t.Fatal(status)
+	}
+}
@@ -0,0 +1,69 @@
+package orders
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDocs(t *testing.T) {
+	r := newRouter(&orderServer{})
+	RegisterOrdersHTTPDocs(r.Group("/api"), "/docs")
+	w := do(r, "GET", "/api/docs", "")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"/api/docs/openapi.json"`) {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	w = do(r, "GET", "/api/docs/openapi.json", "")
+	spec := struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct {
+				Content map[string]interface{} `json:"content"`
+			} `json:"requestBody"`
+		} `json:"paths"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatal(w.Code, err)
+	}
+	if _, ok := spec.Paths["/orders/{id}"]["put"]; !ok {
+		t.Fatal("the spec is missing the archive service", spec.Paths)
+	}
+	if _, ok := spec.Paths["/imports"]["post"].RequestBody.Content["multipart/form-data"]; !ok {
+		t.Fatal(spec.Paths["/imports"])
+	}
+}
+
+// openAPI registers the docs on the router and fetches the spec they serve,
+// decoded into generic values
+func openAPI(t *testing.T, r *gin.Engine) map[string]interface{} {
+	t.Helper()
+	RegisterOrdersHTTPDocs(&r.RouterGroup, "/docs")
+	w := do(r, "GET", "/docs/openapi.json", "")
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatal(w.Code, err)
+	}
+	return spec
+}
+
+// lookup walks the keys of a decoded spec, parameters are looked up by their
+// name, ex. lookup(spec, "paths", "/orders", "get", "parameters", "status")
+func lookup(v interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			v = node[key]
+		case []interface{}:
+			v = nil
+			for _, item := range node {
+				if prm, ok := item.(map[string]interface{}); ok && prm["name"] == key {
+					v = prm
+				}
+			}
+		default:
+			return nil
+		}
+	}
+	return v
+}
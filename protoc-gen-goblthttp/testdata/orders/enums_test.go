@@ -0,0 +1,56 @@
+package orders
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnumParameters(t *testing.T) {
+	s := &orderServer{}
+	r := newRouter(s)
+	for _, tt := range []struct {
+		query  string
+		code   int
+		status OrderStatus
+	}{
+		{query: "ORDER_STATUS_OPEN", code: 200, status: OrderStatus_ORDER_STATUS_OPEN},
+		{query: "2", code: 200, status: OrderStatus_ORDER_STATUS_CLOSED},
+		{query: "ORDER_STATUS_UNSPECIFIED", code: 200},
+		{query: "order_status_open", code: 400},
+		{query: "open", code: 400},
+	} {
+		s.listed = nil
+		w := do(r, "GET", "/orders?status="+tt.query, "")
+		if w.Code != tt.code {
+			t.Fatal(tt.query, w.Code, w.Body.String())
+		}
+		if tt.code == 200 && s.listed.GetStatus() != tt.status {
+			t.Fatal(tt.query, s.listed)
+		}
+	}
+}
+
+func TestEnumOpenAPI(t *testing.T) {
+	spec := openAPI(t, newRouter(&orderServer{}))
+	schema := lookup(spec, "paths", "/orders", "get", "parameters", "status", "schema")
+	want := map[string]interface{}{
+		"type": "string",
+		"enum": []interface{}{
+			"ORDER_STATUS_UNSPECIFIED",
+			"ORDER_STATUS_OPEN",
+			"ORDER_STATUS_CLOSED",
+			"ORDER_STATUS_LEGACY",
+		},
+		"example": "ORDER_STATUS_UNSPECIFIED",
+		"x-enum-descriptions": []interface{}{
+			"",
+			"Being filled in",
+			"Shipped, can no longer be changed",
+			"Closed before shipping was tracked",
+		},
+		"x-enum-deprecated": []interface{}{"ORDER_STATUS_LEGACY"},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Fatal(schema)
+	}
+}
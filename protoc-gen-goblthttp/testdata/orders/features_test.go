@@ -0,0 +1,66 @@
+package orders
+
+import (
+	"context"
+	"testing"
+)
+
+// featureGate enables the features set to true
+type featureGate map[PermissionFeature]bool
+
+func (g featureGate) IsEnabled(_ context.Context, feature PermissionFeature) (bool, error) {
+	return g[feature], nil
+}
+
+func TestFeatureGate(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []HTTPServerOption
+		code int
+		body string
+	}{
+		{name: "no gate", code: 200},
+		{
+			name: "enabled",
+			opts: []HTTPServerOption{
+				WithFeatureGate(featureGate{PermissionFeatureBulkUploads: true}, 404),
+			},
+			code: 200,
+		},
+		{
+			name: "disabled as not found",
+			opts: []HTTPServerOption{WithFeatureGate(featureGate{}, 404)},
+			code: 404,
+			body: "NotFoundError",
+		},
+		{
+			name: "disabled as forbidden",
+			opts: []HTTPServerOption{WithFeatureGate(featureGate{}, 403)},
+			code: 403,
+			body: "FeatureDisabledError",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRouter(&orderServer{}, tt.opts...)
+			w := do(r, "POST", "/orders/a/data", "abc")
+			if w.Code != tt.code || (tt.body != "" && w.Body.String() != tt.body) {
+				t.Fatal(w.Code, w.Body.String())
+			}
+			// rpcs without features are not gated
+			if w := do(r, "GET", "/orders/a", ""); w.Code != 200 {
+				t.Fatal(w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestFeaturesOpenAPI(t *testing.T) {
+	spec := openAPI(t, newRouter(&orderServer{}))
+	features, _ := lookup(spec, "paths", "/orders/{id}/data", "post", "x-features").([]interface{})
+	if len(features) != 1 || features[0] != "bulk_uploads" {
+		t.Fatal(lookup(spec, "paths", "/orders/{id}/data", "post"))
+	}
+	if lookup(spec, "paths", "/orders/{id}", "get", "x-features") != nil {
+		t.Fatal(lookup(spec, "paths", "/orders/{id}", "get"))
+	}
+}
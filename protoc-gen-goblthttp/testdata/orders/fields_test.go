@@ -0,0 +1,58 @@
+package orders
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFieldsParameter(t *testing.T) {
+	r := newRouter(&orderServer{})
+	for _, tt := range []struct {
+		url  string
+		code int
+		body string
+	}{
+		{url: "/orders/a?fields=id", code: 200, body: `{"id":"a"}`},
+		{url: "/orders/a?fields=id,%20version", code: 200, body: `{"id":"a","version":"1"}`},
+		{
+			url:  "/orders?pageSize=2&fields=orders.id,next_page_token",
+			code: 200,
+			body: `{"nextPageToken":"next","orders":[{"id":"0"},{"id":"1"}]}`,
+		},
+		{url: "/orders/a?fields=bogus", code: 400, body: "UnparsableParametersError"},
+		{url: "/orders/a?fields=id.value", code: 400, body: "UnparsableParametersError"},
+	} {
+		w := do(r, "GET", tt.url, "")
+		if w.Code != tt.code || w.Body.String() != tt.body {
+			t.Fatal(tt.url, w.Code, w.Body.String())
+		}
+	}
+
+	// without fields the whole response is written, protojson varies its
+	// whitespace so it is compared decoded
+	w := do(r, "GET", "/orders/a", "")
+	res := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil || w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	want := map[string]interface{}{
+		"id":      "a",
+		"version": "1",
+		"size":    "0",
+		"status":  "ORDER_STATUS_UNSPECIFIED",
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Fatal(res)
+	}
+}
+
+func TestFieldsOpenAPI(t *testing.T) {
+	spec := openAPI(t, newRouter(&orderServer{}))
+	if lookup(spec, "paths", "/orders/{id}", "get", "parameters", "fields", "in") != "query" {
+		t.Fatal(lookup(spec, "paths", "/orders/{id}", "get", "parameters"))
+	}
+	if lookup(spec, "paths", "/orders/{id}", "put", "parameters", "fields") != nil {
+		t.Fatal(lookup(spec, "paths", "/orders/{id}", "put", "parameters"))
+	}
+}
@@ -0,0 +1,37 @@
+package orders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeaderBinding(t *testing.T) {
+	s := &orderServer{}
+	r := newRouter(s)
+	req := httptest.NewRequest("GET", "/orders/a?tenant=query&session=query", nil)
+	req.Header.Set("X-Tenant", "t1")
+	req.Header.Set("X-Priority", "3")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if s.queried.GetTenant() != "t1" || s.queried.GetPriority() != 3 ||
+		s.queried.GetSession() != "s1" {
+		t.Fatal(s.queried)
+	}
+
+	w = do(r, "GET", "/orders/a?tenant=query", "")
+	if w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if s.queried.Tenant != nil || s.queried.Priority != nil || s.queried.Session != nil {
+		t.Fatal("fields bound from absent headers and cookies", s.queried)
+	}
+
+	if w := do(r, "GET", "/orders/a", "", "X-Priority", "high"); w.Code != 400 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+}
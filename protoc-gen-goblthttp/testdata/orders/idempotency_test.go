@@ -0,0 +1,73 @@
+package orders
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// replayStore answers every key with the response it holds
+type replayStore struct {
+	response IdempotencyResponse
+}
+
+func (s replayStore) Begin(
+	_ context.Context,
+	_ string,
+	fingerprint string,
+) (*IdempotencyRecord, error) {
+	return &IdempotencyRecord{Fingerprint: fingerprint, Completed: true, Response: s.response}, nil
+}
+
+func (replayStore) Complete(context.Context, string, IdempotencyResponse) error {
+	return nil
+}
+
+func (replayStore) Abort(context.Context, string) error {
+	return nil
+}
+
+func TestIdempotencyReplaysHeaders(t *testing.T) {
+	s := &orderServer{}
+	r := newRouter(s)
+	w := do(
+		r,
+		"POST",
+		"/orders/a",
+		`{"version":"1"}`,
+		"Idempotency-Key",
+		"k1",
+		"Origin",
+		"https://app.example.com",
+	)
+	if w.Code != 200 || w.Header().Get("ETag") != `"2"` {
+		t.Fatal(w.Code, w.Header())
+	}
+	body := w.Body.String()
+	w = do(r, "POST", "/orders/a", `{"version":"1"}`, "Idempotency-Key", "k1")
+	if w.Code != 200 || w.Header().Get("Idempotent-Replayed") != "true" || s.calls != 1 {
+		t.Fatal(w.Code, w.Header(), s.calls)
+	}
+	if w.Header().Get("ETag") != `"2"` || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatal(w.Header())
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatal("cors headers of the first call were replayed", w.Header())
+	}
+	if w.Body.String() != body {
+		t.Fatal(w.Body.String(), body)
+	}
+
+	r = newRouter(s, WithIdempotencyStore(replayStore{IdempotencyResponse{
+		Status: http.StatusCreated,
+		Header: http.Header{"Location": {"/orders/b"}, "Content-Type": {"text/plain"}},
+		Body:   []byte("created"),
+	}}))
+	w = do(r, "POST", "/orders/b", `{}`, "Idempotency-Key", "k2")
+	if w.Code != http.StatusCreated || w.Header().Get("Location") != "/orders/b" || s.calls != 1 {
+		t.Fatal(w.Code, w.Header(), s.calls)
+	}
+	if w.Header().Get("Content-Type") != "text/plain" || w.Body.String() != "created" {
+		t.Fatal(w.Header(), w.Body.String())
+	}
+}
@@ -0,0 +1,57 @@
+package orders
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRPCJSONOptions(t *testing.T) {
+	r := newRouter(&orderServer{})
+	w := do(r, "PATCH", "/orders/a", `{}`)
+	res := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil || w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	// the rpc writes enums as numbers and leaves out zero values, overriding
+	// the EmitUnpopulated the package is generated with
+	want := map[string]interface{}{"id": "a", "status": float64(2)}
+	if !reflect.DeepEqual(res, want) {
+		t.Fatal(res)
+	}
+	if !strings.Contains(w.Body.String(), "\n  \"id\"") {
+		t.Fatal("response is not indented", w.Body.String())
+	}
+
+	// other rpcs keep the options of the package
+	w = do(r, "GET", "/orders/a", "")
+	res = map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil || w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if res["status"] != "ORDER_STATUS_UNSPECIFIED" || res["size"] != "0" {
+		t.Fatal(res)
+	}
+}
+
+func TestRPCJSONOptionsOpenAPI(t *testing.T) {
+	spec := openAPI(t, newRouter(&orderServer{}))
+	ref := lookup(spec, "paths", "/orders/{id}", "patch", "responses", "200",
+		"content", "application/json", "schema", "$ref")
+	if ref != "#/components/schemas/EnumNumbersOrder" {
+		t.Fatal(ref)
+	}
+	status := lookup(spec, "components", "schemas", "EnumNumbersOrder", "properties", "status")
+	if lookup(status, "type") != "integer" || !reflect.DeepEqual(
+		lookup(status, "x-enum-varnames"),
+		[]interface{}{
+			"ORDER_STATUS_UNSPECIFIED",
+			"ORDER_STATUS_OPEN",
+			"ORDER_STATUS_CLOSED",
+			"ORDER_STATUS_LEGACY",
+		},
+	) {
+		t.Fatal(status)
+	}
+}
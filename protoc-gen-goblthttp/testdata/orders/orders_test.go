@@ -0,0 +1,339 @@
+package orders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/betalixt/gorr"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type orderServer struct {
+	deadline time.Time
+	panicked bool
+	calls    int
+	updated  *UpdateOrderCommand
+	queried  *GetOrderQuery
+	listed   *ListOrdersQuery
+}
+
+func (s *orderServer) GetOrder(_ context.Context, q *GetOrderQuery) (*Order, error) {
+	s.queried = q
+	return &Order{Id: q.Id, Version: 1}, nil
+}
+
+func (s *orderServer) ListOrders(
+	_ context.Context,
+	q *ListOrdersQuery,
+) (*ListOrdersResponse, error) {
+	s.listed = q
+	res := &ListOrdersResponse{}
+	for idx := int32(0); idx < q.PageSize; idx++ {
+		res.Orders = append(res.Orders, &Order{Id: q.PageToken + strconv.Itoa(int(idx))})
+	}
+	if q.PageToken == "" {
+		res.NextPageToken = "next"
+	}
+	return res, nil
+}
+
+func (s *orderServer) CreateOrder(ctx context.Context, q *CreateOrderCommand) (*Order, error) {
+	s.calls++
+	s.deadline, _ = ctx.Deadline()
+	switch q.Id {
+	case "panic":
+		if !s.panicked {
+			s.panicked = true
+			panic("boom")
+		}
+	case "slow":
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &Order{Id: q.Id, Version: q.Version + 1}, nil
+}
+
+func (s *orderServer) UpdateOrder(_ context.Context, q *UpdateOrderCommand) (*Order, error) {
+	s.updated = q
+	return &Order{Id: q.Id, Status: OrderStatus_ORDER_STATUS_CLOSED}, nil
+}
+
+func (s *orderServer) UploadOrder(ctx context.Context, q *UploadOrderCommand) (*Order, error) {
+	if len(q.Data) != 0 {
+		return nil, errors.New("streamed field was read into the input")
+	}
+	data, err := io.ReadAll(q.DataReader(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &Order{Id: q.Id, Size: int64(len(data))}, nil
+}
+
+func (s *orderServer) ImportOrders(ctx context.Context, q *ImportOrdersCommand) (*Order, error) {
+	data, err := io.ReadAll(q.FileReader(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &Order{Id: q.Source + ":" + string(data), Size: int64(len(data))}, nil
+}
+
+func (s *orderServer) AttachOrderFile(
+	ctx context.Context,
+	q *AttachOrderFileCommand,
+) (*Order, error) {
+	data, err := io.ReadAll(q.FileReader(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &Order{Id: q.Id, Size: int64(len(data))}, nil
+}
+
+type archiveServer struct{}
+
+func (archiveServer) ArchiveOrder(context.Context, *ArchiveOrderCommand) (*Empty, error) {
+	return &Empty{}, nil
+}
+
+func (archiveServer) GetArchiveStatus(context.Context, *GetArchiveStatusQuery) (*Empty, error) {
+	return &Empty{}, nil
+}
+
+// authorizer allows every call unless deny is set
+type authorizer struct {
+	deny bool
+}
+
+func (a authorizer) Authorize(context.Context, *HTTPRouteInfo, proto.Message) (bool, error) {
+	return !a.deny, nil
+}
+
+func (a authorizer) CheckResource(context.Context, string, string, ResourceAction) (bool, error) {
+	return !a.deny, nil
+}
+
+func newRouter(s *orderServer, opts ...HTTPServerOption) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard), func(c *gin.Context) {
+		c.Next()
+		var gerr *gorr.Error
+		if len(c.Errors) > 0 && errors.As(c.Errors.Last().Err, &gerr) {
+			c.String(gerr.ErrorCode.Code, gerr.ErrorCode.Message)
+		}
+	})
+	opts = append([]HTTPServerOption{
+		WithAuthorizer(authorizer{}),
+		WithCORSPreflights(&sync.Map{}),
+	}, opts...)
+	RegisterOrdersHTTPServer(&r.RouterGroup, s, opts...)
+	RegisterArchiveHTTPServer(&r.RouterGroup, archiveServer{}, opts...)
+	return r
+}
+
+func do(r http.Handler, method, url, body string, hdr ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	for i := 0; i+1 < len(hdr); i += 2 {
+		req.Header.Set(hdr[i], hdr[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency(t *testing.T) {
+	s := &orderServer{}
+	r := newRouter(s)
+	w := do(r, "POST", "/orders/a", `{}`, "Idempotency-Key", "k1", "Authorization", "Bearer one")
+	if w.Code != 200 || s.calls != 1 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	w = do(r, "POST", "/orders/a", `{}`, "Idempotency-Key", "k1", "Authorization", "Bearer one")
+	if w.Code != 200 || w.Header().Get("Idempotent-Replayed") != "true" || s.calls != 1 {
+		t.Fatal(w.Code, w.Header(), s.calls)
+	}
+	w = do(r, "POST", "/orders/a", `{}`, "Idempotency-Key", "k1", "Authorization", "Bearer two")
+	if w.Code != 200 || w.Header().Get("Idempotent-Replayed") != "" || s.calls != 2 {
+		t.Fatal(w.Code, w.Header(), s.calls)
+	}
+	if w := do(r, "POST", "/orders/panic", `{}`, "Idempotency-Key", "k2"); w.Code != 500 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	w = do(r, "POST", "/orders/panic", `{}`, "Idempotency-Key", "k2")
+	if w.Code != 200 || w.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatal(w.Code, w.Body.String())
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s := &orderServer{}
+	r := newRouter(s)
+	do(r, "POST", "/orders/a", `{}`, "grpc-timeout", "100m")
+	if d := time.Until(s.deadline); d > 100*time.Millisecond {
+		t.Fatal(d)
+	}
+	for _, hdr := range []string{"2562048H", "99999999H", "bogus"} {
+		do(r, "POST", "/orders/a", `{}`, "grpc-timeout", hdr)
+		if d := time.Until(s.deadline); d > 2*time.Second || d < time.Second {
+			t.Fatal(hdr, d)
+		}
+	}
+	w := do(r, "POST", "/orders/slow", `{}`, "Request-Timeout", "0.05")
+	if w.Code != 504 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	r := newRouter(&orderServer{})
+	w := do(r, "POST", "/orders/a", `{}`, "If-Match", `W/"2", "3"`)
+	if w.Code != 200 || w.Header().Get("ETag") != `"4"` {
+		t.Fatal(w.Code, w.Header(), w.Body.String())
+	}
+	for _, hdr := range []string{`"x"`, `W/"3"`, `"2", "3"`} {
+		if w := do(r, "POST", "/orders/a", `{}`, "If-Match", hdr); w.Code != 412 {
+			t.Fatal(hdr, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	r := newRouter(&orderServer{})
+	w := do(r, "GET", "/orders/a", "")
+	if w.Code != 200 || w.Header().Get("Cache-Control") == "" {
+		t.Fatal(w.Code, w.Header())
+	}
+	if w := do(r, "GET", "/orders/a", "", "If-None-Match", w.Header().Get("ETag")); w.Code != 304 {
+		t.Fatal(w.Code)
+	}
+	if w := do(r, "POST", "/orders/a", `{}`); w.Header().Get("Cache-Control") != "" {
+		t.Fatal(w.Header())
+	}
+}
+
+func TestInterceptorMetadataAndStatus(t *testing.T) {
+	auth := func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get("authorization")) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing token")
+		}
+		if got := md.Get("x-trace-bin"); len(got) != 1 || got[0] != "hi" {
+			return nil, status.Error(codes.InvalidArgument, "bad trace")
+		}
+		return handler(ctx, req)
+	}
+	r := newRouter(&orderServer{}, WithUnaryInterceptors(auth))
+	if w := do(r, "POST", "/orders/a", `{}`); w.Code != 401 ||
+		w.Body.String() != "UnauthenticatedError" {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	w := do(r, "POST", "/orders/a", `{}`, "Authorization", "Bearer a", "X-Trace-Bin", "aGk=")
+	if w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+}
+
+func TestCORSSharedRoute(t *testing.T) {
+	r := newRouter(&orderServer{})
+	w := do(r, "OPTIONS", "/orders/a", "", "Origin", "https://app.example.com")
+	if w.Code != 204 || w.Header().Get("Access-Control-Allow-Methods") != "GET, POST, PATCH" ||
+		w.Header().Get("Allow") != "OPTIONS, GET, POST, PATCH, PUT" {
+		t.Fatal(w.Code, w.Header())
+	}
+	w = do(r, "OPTIONS", "/orders/a", "", "Origin", "https://admin.example.com")
+	if w.Code != 204 || w.Header().Get("Access-Control-Allow-Methods") != "PUT" ||
+		w.Header().Get("Access-Control-Allow-Origin") != "https://admin.example.com" {
+		t.Fatal(w.Code, w.Header())
+	}
+}
+
+func TestStreamedFields(t *testing.T) {
+	r := newRouter(&orderServer{})
+	w := do(r, "POST", "/orders/a/data", "streamed body")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"size":"13"`) {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	data, _ := io.ReadAll(
+		(&UploadOrderCommand{Data: []byte("abc")}).DataReader(context.Background()),
+	)
+	if string(data) != "abc" {
+		t.Fatal(string(data))
+	}
+
+	buf := &bytes.Buffer{}
+	mw := multipart.NewWriter(buf)
+	mw.WriteField("source", "csv")
+	fw, _ := mw.CreateFormFile("file", "orders.csv")
+	fw.Write([]byte("a,b"))
+	mw.Close()
+	w = do(r, "POST", "/imports", buf.String(), "Content-Type", mw.FormDataContentType())
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"id":"csv:a,b"`) {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	buf = &bytes.Buffer{}
+	mw = multipart.NewWriter(buf)
+	mw.WriteField("source", "csv")
+	mw.Close()
+	w = do(r, "POST", "/imports", buf.String(), "Content-Type", mw.FormDataContentType())
+	if w.Code != 400 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if w := do(r, "POST", "/imports", `{}`, "Content-Type", "application/json"); w.Code != 415 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+}
+
+func TestStreamedFormValueOrder(t *testing.T) {
+	r := newRouter(&orderServer{})
+	buf := &bytes.Buffer{}
+	mw := multipart.NewWriter(buf)
+	fw, _ := mw.CreateFormFile("file", "orders.csv")
+	fw.Write([]byte("a,b"))
+	mw.WriteField("source", "csv")
+	mw.Close()
+	w := do(r, "POST", "/imports", buf.String(), "Content-Type", mw.FormDataContentType())
+	if w.Code != 400 || w.Body.String() != "MissingRequiredParametersError" {
+		t.Fatal(w.Code, w.Body.String())
+	}
+
+	buf = &bytes.Buffer{}
+	mw = multipart.NewWriter(buf)
+	mw.WriteField("source", "csv")
+	fw, _ = mw.CreateFormFile("file", "orders.csv")
+	fw.Write([]byte("a,b"))
+	mw.WriteField("source", "xml")
+	mw.Close()
+	w = do(r, "POST", "/imports", buf.String(), "Content-Type", mw.FormDataContentType())
+	if w.Code != 400 || w.Body.String() != "FormValueAfterFileError" {
+		t.Fatal(w.Code, w.Body.String())
+	}
+
+	buf = &bytes.Buffer{}
+	mw = multipart.NewWriter(buf)
+	fw, _ = mw.CreateFormFile("file", "orders.csv")
+	fw.Write([]byte("a,b"))
+	mw.Close()
+	w = do(r, "POST", "/orders/a/file", buf.String(), "Content-Type", mw.FormDataContentType())
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"size":"3"`) {
+		t.Fatal(w.Code, w.Body.String())
+	}
+}
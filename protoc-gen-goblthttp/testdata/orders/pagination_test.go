@@ -0,0 +1,34 @@
+package orders
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPagination(t *testing.T) {
+	r := newRouter(&orderServer{})
+	w := do(r, "GET", "/orders?pageSize=100&status=ORDER_STATUS_OPEN", "")
+	res := struct {
+		Orders []struct {
+			ID string `json:"id"`
+		} `json:"orders"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil || w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if len(res.Orders) != 50 {
+		t.Fatal(len(res.Orders))
+	}
+	link := w.Header().Get("Link")
+	if link != `</orders?pageSize=100&pageToken=next&status=ORDER_STATUS_OPEN>; rel="next"` {
+		t.Fatal(link)
+	}
+
+	w = do(r, "GET", "/orders?pageSize=2&pageToken=next", "")
+	if w.Code != 200 || w.Header().Get("Link") != "" {
+		t.Fatal(w.Code, w.Header())
+	}
+	if w := do(r, "GET", "/orders?pageSize=-1", ""); w.Code != 400 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+}
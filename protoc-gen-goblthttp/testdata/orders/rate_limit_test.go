@@ -0,0 +1,53 @@
+package orders
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestRateLimit(t *testing.T) {
+	for _, clientIP := range []bool{false, true} {
+		opts := []HTTPServerOption{}
+		if clientIP {
+			opts = append(opts, WithRateLimitClientIP())
+		}
+		r := newRouter(&orderServer{}, opts...)
+		call := func(addr string, forwarded string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", "/archive/status", nil)
+			req.RemoteAddr = addr + ":1234"
+			req.Header.Set("X-Forwarded-For", forwarded)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			return w
+		}
+
+		for idx, remaining := range []string{"1", "0"} {
+			if clientIP {
+				// every call is forwarded for a different address
+				remaining = "1"
+			}
+			w := call("192.0.2.1", "198.51.100."+strconv.Itoa(idx))
+			if w.Code != 200 || w.Header().Get("RateLimit-Limit") != "2" ||
+				w.Header().Get("RateLimit-Remaining") != remaining {
+				t.Fatal(w.Code, w.Header())
+			}
+		}
+		w := call("192.0.2.1", "198.51.100.2")
+		if clientIP {
+			if w.Code != 200 {
+				t.Fatal(w.Code, w.Header())
+			}
+			continue
+		}
+		if w.Code != 429 || w.Header().Get("Retry-After") != "30" ||
+			w.Header().
+				Get("RateLimit-Remaining") !=
+				"0" || w.Header().Get("RateLimit-Reset") != "60" {
+			t.Fatal(w.Code, w.Header())
+		}
+		if w := call("192.0.2.2", "198.51.100.2"); w.Code != 200 {
+			t.Fatal(w.Code, w.Header())
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package orders
+
+import (
+	"context"
+	"testing"
+)
+
+// resourceAuthorizer allows every rpc, recording the resources checked and
+// denying access to the denied ones
+type resourceAuthorizer struct {
+	authorizer
+	checked *[]string
+	denied  string
+}
+
+func (a resourceAuthorizer) CheckResource(
+	_ context.Context,
+	resourceType string,
+	id string,
+	action ResourceAction,
+) (bool, error) {
+	*a.checked = append(*a.checked, resourceType+":"+id+":"+string(action))
+	return id != a.denied, nil
+}
+
+func TestResourceChecks(t *testing.T) {
+	checked := []string{}
+	r := newRouter(
+		&orderServer{},
+		WithAuthorizer(resourceAuthorizer{checked: &checked, denied: "b"}),
+	)
+	if w := do(r, "GET", "/orders/a", ""); w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if w := do(r, "PUT", "/orders/a", `{}`); w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if len(checked) != 2 || checked[0] != "order:a:read" || checked[1] != "order:a:write" {
+		t.Fatal(checked)
+	}
+
+	if w := do(r, "GET", "/orders/b", ""); w.Code != 403 || w.Body.String() != "ForbiddenError" {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if w := do(r, "PUT", "/orders/b", `{}`); w.Code != 403 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+
+	// rpcs without resource fields are not checked
+	checked = checked[:0]
+	if w := do(r, "POST", "/orders/b", `{}`); w.Code != 200 || len(checked) != 0 {
+		t.Fatal(w.Code, checked)
+	}
+}
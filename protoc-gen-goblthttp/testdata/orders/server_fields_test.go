@@ -0,0 +1,70 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestServerFields(t *testing.T) {
+	s := &orderServer{}
+	r := newRouter(s)
+	if w := do(r, "PATCH", "/orders/a", `{"updatedBy": "client"}`); w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if s.updated.UpdatedBy != "" {
+		t.Fatal("the value sent by the client was kept", s.updated.UpdatedBy)
+	}
+
+	calls := []string{}
+	r = newRouter(s, WithServerFieldPopulator(ServerFieldPopulatorFunc(func(
+		_ context.Context,
+		info *HTTPRouteInfo,
+		input proto.Message,
+		fields []protoreflect.FieldDescriptor,
+	) error {
+		for _, fd := range fields {
+			calls = append(calls, info.FullMethod+":"+string(fd.Name()))
+			input.ProtoReflect().Set(fd, protoreflect.ValueOfString("server"))
+		}
+		return nil
+	})))
+	if w := do(r, "PATCH", "/orders/a", `{"updatedBy": "client"}`); w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if s.updated.UpdatedBy != "server" {
+		t.Fatal(s.updated.UpdatedBy)
+	}
+	// rpcs without server populated fields do not call the populator
+	if w := do(r, "GET", "/orders/a", ""); w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if len(calls) != 1 || calls[0] != "/blthttptest.Orders/UpdateOrder:updated_by" {
+		t.Fatal(calls)
+	}
+
+	s.updated = nil
+	r = newRouter(s, WithServerFieldPopulator(ServerFieldPopulatorFunc(func(
+		context.Context,
+		*HTTPRouteInfo,
+		proto.Message,
+		[]protoreflect.FieldDescriptor,
+	) error {
+		return errors.New("no claims")
+	})))
+	do(r, "PATCH", "/orders/a", `{}`)
+	if s.updated != nil {
+		t.Fatal("the rpc was invoked after the populator failed")
+	}
+}
+
+func TestServerFieldsOpenAPI(t *testing.T) {
+	spec := openAPI(t, newRouter(&orderServer{}))
+	props := lookup(spec, "components", "schemas", "UpdateOrderCommand", "properties")
+	if lookup(props, "note") == nil || lookup(props, "updatedBy") != nil {
+		t.Fatal(props)
+	}
+}
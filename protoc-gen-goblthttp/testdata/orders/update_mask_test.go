@@ -0,0 +1,66 @@
+package orders
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// populator sets the server populated fields of updates
+type populator struct{}
+
+func (populator) PopulateServerFields(
+	_ context.Context,
+	_ *HTTPRouteInfo,
+	input proto.Message,
+	fields []protoreflect.FieldDescriptor,
+) error {
+	for _, fd := range fields {
+		input.ProtoReflect().Set(fd, protoreflect.ValueOfString("server"))
+	}
+	return nil
+}
+
+func TestUpdateMask(t *testing.T) {
+	s := &orderServer{}
+	r := newRouter(s, WithServerFieldPopulator(populator{}))
+	for body, paths := range map[string][]string{
+		`{"note": "a"}`:                              {"note"},
+		`{"note": null}`:                             {"note"},
+		`{"address": null}`:                          {"address"},
+		`{"address": {}}`:                            {},
+		`{"address": {"city": "x"}}`:                 {"address.city"},
+		`{"address": {"city": null, "street": "y"}}`: {"address.city", "address.street"},
+		`{"updatedBy": "client"}`:                    {},
+		`{}`:                                         {},
+	} {
+		w := do(r, "PATCH", "/orders/a", body)
+		if w.Code != 200 {
+			t.Fatal(body, w.Code, w.Body.String())
+		}
+		got := s.updated.GetUpdateMask().GetPaths()
+		if len(got) != 0 || len(paths) != 0 {
+			if !reflect.DeepEqual(got, paths) {
+				t.Fatal(body, got)
+			}
+		}
+		if s.updated.GetUpdatedBy() != "server" {
+			t.Fatal(body, s.updated.GetUpdatedBy())
+		}
+	}
+
+	do(r, "PATCH", "/orders/a", `{"note": "a", "updateMask": "address"}`)
+	if got := s.updated.GetUpdateMask().GetPaths(); !reflect.DeepEqual(got, []string{"address"}) {
+		t.Fatal(got)
+	}
+
+	for _, body := range []string{``, `{"note": 1}`, `{"address": "x"}`, `[]`} {
+		if w := do(r, "PATCH", "/orders/a", body); w.Code != 400 ||
+			w.Body.String() != "UnparsableParametersError" {
+			t.Fatal(body, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package snakeorders
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+// orderServer serves the orders, the rpcs not tested are not called
+type orderServer struct {
+	OrdersHTTPServer
+}
+
+func (orderServer) ListOrders(_ context.Context, q *ListOrdersQuery) (*ListOrdersResponse, error) {
+	res := &ListOrdersResponse{NextPageToken: "next"}
+	for idx := int32(0); idx < q.PageSize; idx++ {
+		res.Orders = append(res.Orders, &Order{Id: "o"})
+	}
+	return res, nil
+}
+
+type authorizer struct{}
+
+func (authorizer) Authorize(context.Context, *HTTPRouteInfo, proto.Message) (bool, error) {
+	return true, nil
+}
+
+func (authorizer) CheckResource(context.Context, string, string, ResourceAction) (bool, error) {
+	return true, nil
+}
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard))
+	RegisterOrdersHTTPServer(&r.RouterGroup, orderServer{}, WithAuthorizer(authorizer{}))
+	RegisterOrdersHTTPDocs(&r.RouterGroup, "/docs")
+	return r
+}
+
+func get(r *gin.Engine, url string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+	return w
+}
+
+// TestProtoNames checks servers generated with JSONProtoNames name query
+// parameters and responses after the proto fields
+func TestProtoNames(t *testing.T) {
+	r := newRouter()
+	w := get(r, "/orders?page_size=2&fields=orders.id,next_page_token")
+	if w.Code != 200 ||
+		w.Body.String() != `{"next_page_token":"next","orders":[{"id":"o"},{"id":"o"}]}` {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if link := w.Header().Get("Link"); link != `</orders?fields=orders.id%2Cnext_page_token&page_size=2&page_token=next>; rel="next"` {
+		t.Fatal(link)
+	}
+	w = get(r, "/orders?pageSize=2")
+	res := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil || w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if _, ok := res["orders"]; ok || res["next_page_token"] != "next" {
+		t.Fatal("camel case query parameters were bound", res)
+	}
+}
+
+func TestProtoNamesOpenAPI(t *testing.T) {
+	w := get(newRouter(), "/docs/openapi.json")
+	spec := struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name string `json:"name"`
+			} `json:"parameters"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatal(w.Code, err)
+	}
+	names := map[string]bool{}
+	for _, prm := range spec.Paths["/orders"]["get"].Parameters {
+		names[prm.Name] = true
+	}
+	if !names["page_size"] || !names["page_token"] || names["pageSize"] {
+		t.Fatal(names)
+	}
+	if _, ok := spec.Components.Schemas["ListOrdersResponse"].Properties["next_page_token"]; !ok {
+		t.Fatal(spec.Components.Schemas["ListOrdersResponse"])
+	}
+}
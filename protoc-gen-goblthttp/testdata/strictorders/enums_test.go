@@ -0,0 +1,75 @@
+package strictorders
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/betalixt/gorr"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+// orderServer serves the list of orders, recording the query, the other rpcs
+// are not called
+type orderServer struct {
+	OrdersHTTPServer
+	listed *ListOrdersQuery
+}
+
+func (s *orderServer) ListOrders(
+	_ context.Context,
+	q *ListOrdersQuery,
+) (*ListOrdersResponse, error) {
+	s.listed = q
+	return &ListOrdersResponse{}, nil
+}
+
+type authorizer struct{}
+
+func (authorizer) Authorize(context.Context, *HTTPRouteInfo, proto.Message) (bool, error) {
+	return true, nil
+}
+
+func (authorizer) CheckResource(context.Context, string, string, ResourceAction) (bool, error) {
+	return true, nil
+}
+
+// TestEnumParameters checks the enums of servers generated with
+// EnumCaseInsensitive and RejectUnspecifiedEnums
+func TestEnumParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard), func(c *gin.Context) {
+		c.Next()
+		var gerr *gorr.Error
+		if len(c.Errors) > 0 && errors.As(c.Errors.Last().Err, &gerr) {
+			c.String(gerr.ErrorCode.Code, gerr.ErrorCode.Message)
+		}
+	})
+	s := &orderServer{}
+	RegisterOrdersHTTPServer(&r.RouterGroup, s, WithAuthorizer(authorizer{}))
+	for _, tt := range []struct {
+		query  string
+		code   int
+		status OrderStatus
+	}{
+		{query: "ORDER_STATUS_OPEN", code: 200, status: OrderStatus_ORDER_STATUS_OPEN},
+		{query: "order_status_closed", code: 200, status: OrderStatus_ORDER_STATUS_CLOSED},
+		{query: "1", code: 200, status: OrderStatus_ORDER_STATUS_OPEN},
+		{query: "ORDER_STATUS_UNSPECIFIED", code: 400},
+		{query: "0", code: 400},
+	} {
+		s.listed = nil
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/orders?status="+tt.query, nil))
+		if w.Code != tt.code {
+			t.Fatal(tt.query, w.Code, w.Body.String())
+		}
+		if tt.code == 200 && s.listed.GetStatus() != tt.status {
+			t.Fatal(tt.query, s.listed)
+		}
+	}
+}
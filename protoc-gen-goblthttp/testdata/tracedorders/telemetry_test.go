@@ -0,0 +1,166 @@
+package tracedorders
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/protobuf/proto"
+)
+
+// orderServer gets orders, failing for the order named fail, the other rpcs
+// are not called
+type orderServer struct {
+	OrdersHTTPServer
+}
+
+func (orderServer) GetOrder(_ context.Context, q *GetOrderQuery) (*Order, error) {
+	if q.Id == "fail" {
+		return nil, errors.New("boom")
+	}
+	return &Order{Id: q.Id}, nil
+}
+
+type authorizer struct{}
+
+func (authorizer) Authorize(context.Context, *HTTPRouteInfo, proto.Message) (bool, error) {
+	return true, nil
+}
+
+func (authorizer) CheckResource(context.Context, string, string, ResourceAction) (bool, error) {
+	return true, nil
+}
+
+func newRouter(opts ...HTTPServerOption) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard))
+	opts = append([]HTTPServerOption{WithAuthorizer(authorizer{})}, opts...)
+	RegisterOrdersHTTPServer(&r.RouterGroup, orderServer{}, opts...)
+	return r
+}
+
+func get(r *gin.Engine, url string, hdr ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", url, nil)
+	for i := 0; i+1 < len(hdr); i += 2 {
+		req.Header.Set(hdr[i], hdr[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func attributes(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	found := map[attribute.Key]attribute.Value{}
+	for _, kv := range kvs {
+		found[kv.Key] = kv.Value
+	}
+	return found
+}
+
+func TestSpans(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	r := newRouter(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))),
+		WithPropagator(propagation.TraceContext{}),
+	)
+	parent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	if w := get(r, "/orders/a", "traceparent", parent); w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	get(r, "/orders/fail")
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatal(len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "blthttptest.Orders/GetOrder" ||
+		span.Parent().TraceID().String() != "0af7651916cd43dd8448eb211c80319c" {
+		t.Fatal(span.Name(), span.Parent())
+	}
+	attrs := attributes(span.Attributes())
+	if attrs["http.route"].AsString() != "/orders/{id}" ||
+		attrs["http.request.method"].AsString() != "GET" ||
+		attrs["rpc.service"].AsString() != "blthttptest.Orders" ||
+		attrs["rpc.method"].AsString() != "GetOrder" ||
+		attrs["http.response.status_code"].AsInt64() != 200 {
+		t.Fatal(attrs)
+	}
+	if span.Status().Code == codes.Error {
+		t.Fatal(span.Status())
+	}
+
+	failed := spans[1]
+	if failed.Status().Code != codes.Error || len(failed.Events()) != 1 ||
+		failed.Events()[0].Name != "exception" {
+		t.Fatal(failed.Status(), failed.Events())
+	}
+	if code := attributes(failed.Attributes())["http.response.status_code"]; code.AsInt64() != 500 {
+		t.Fatal(code)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	r := newRouter(WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	get(r, "/orders/a")
+	get(r, "/orders/a")
+	get(r, "/orders/fail")
+
+	rm := metricdata.ResourceMetrics{}
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]map[int64]uint64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			counts[m.Name] = map[int64]uint64{}
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					status, _ := dp.Attributes.Value("http.response.status_code")
+					route, _ := dp.Attributes.Value("http.route")
+					if route.AsString() != "/orders/{id}" {
+						t.Fatal(m.Name, route)
+					}
+					counts[m.Name][status.AsInt64()] += dp.Count
+				}
+			case metricdata.Histogram[int64]:
+				for _, dp := range data.DataPoints {
+					status, _ := dp.Attributes.Value("http.response.status_code")
+					counts[m.Name][status.AsInt64()] += dp.Count
+				}
+			}
+		}
+	}
+	// the size of errors is unknown as they are written by a later handler
+	for name, want := range map[string]map[int64]uint64{
+		"http.server.request.duration":   {200: 2, 500: 1},
+		"http.server.request.body.size":  {200: 2, 500: 1},
+		"http.server.response.body.size": {200: 2},
+	} {
+		if !reflect.DeepEqual(counts[name], want) {
+			t.Fatal(name, counts[name])
+		}
+	}
+}
+
+// TestNoopTelemetry checks the servers work with the global providers, which
+// are no-ops until an SDK is installed
+func TestNoopTelemetry(t *testing.T) {
+	if w := get(newRouter(), "/orders/a"); w.Code != 200 {
+		t.Fatal(w.Code, w.Body.String())
+	}
+}